@@ -0,0 +1,317 @@
+package ht16k33
+
+import "time"
+
+// Animation is a non-blocking effect run by the Animator of a Device. New
+// effects implement it instead of adding their own state to the Device.
+//
+// Animationは、DeviceのAnimatorが動かすノンブロッキングの効果。新しい効果は
+// Deviceに独自の状態を追加する代わりにこれを実装する。
+type Animation interface {
+	// Start is called once when the animation is added, with the time.
+	// Startは、アニメーションを追加したときにその時刻とともに一度だけ呼ばれる。
+	Start(d *Device, now time.Time)
+	// Step advances the animation to now. It returns true if it changed the
+	// buffer, so that the display is sent once for all animations.
+	// Stepは、アニメーションをnowまで進める。バッファを変更した場合はtrueを
+	// 返し、すべてのアニメーションに対してディスプレイを一度だけ送る。
+	Step(d *Device, now time.Time) bool
+	// Done reports whether the animation has finished. Finished animations
+	// are removed from the Animator.
+	// Doneは、アニメーションが終わったかを返す。終わったアニメーションは
+	// Animatorから取り除かれる。
+	Done() bool
+}
+
+// Stopper is implemented by Animations that can jump to their end, so that
+// StopAnimation leaves the display as it would be after them. Animations
+// without it are just dropped.
+//
+// Stopperは、終わりへ飛べるAnimationが実装するもので、StopAnimationは
+// ディスプレイをそれらの後の状態にする。これを持たないアニメーションは
+// 単に取り除く。
+type Stopper interface {
+	// Stop ends the animation at once, so that Done reports true. It only
+	// changes the buffer, which StopAnimation sends once for all of them.
+	// Stopはアニメーションをすぐに終え、Doneがtrueを返すようにする。変更は
+	// バッファだけで、StopAnimationがすべての分をまとめて一度送る。
+	Stop(d *Device)
+}
+
+// Animator runs a set of Animations together. Each Device has one, driven
+// by Device.Update.
+//
+// Animatorは、複数のAnimationを一緒に動かす。各Deviceが1つ持ち、
+// Device.Updateで動かす。
+type Animator struct {
+	entries  []animatorEntry
+	stepping bool // Step is running, so that Stop waits for its end
+	stopping bool // Stop was called during Step
+}
+
+// animatorEntry is a running animation and the functions to call when it
+// finishes.
+//
+// animatorEntryは、動いているアニメーションと、それが終わったときに呼び出す
+// 関数。
+type animatorEntry struct {
+	animation Animation
+	done      []func()
+}
+
+// Add starts an animation and adds it to the Animator.
+//
+// Addは、アニメーションを開始してAnimatorに追加する。
+func (a *Animator) Add(d *Device, animation Animation, now time.Time) {
+	animation.Start(d, now)
+	a.entries = append(a.entries, animatorEntry{animation: animation})
+}
+
+// OnDone arranges for f to be called by Step once animation has finished
+// and been removed. It returns false, without calling f, if animation is
+// not running on the Animator.
+//
+// OnDoneは、animationが終わって取り除かれたときにStepからfを呼び出すように
+// する。animationがAnimatorで動いていなければ、fを呼び出さずにfalseを返す。
+func (a *Animator) OnDone(animation Animation, f func()) bool {
+	for i := range a.entries {
+		if a.entries[i].animation == animation {
+			a.entries[i].done = append(a.entries[i].done, f)
+			return true
+		}
+	}
+	return false
+}
+
+// Step steps all animations and removes the finished ones, then calls the
+// functions registered with OnDone for them. It returns true if any of the
+// animations changed the buffer.
+//
+// Stepは、すべてのアニメーションを進めて終わったものを取り除き、それらに
+// OnDoneで登録した関数を呼び出す。いずれかのアニメーションがバッファを
+// 変更した場合はtrueを返す。
+func (a *Animator) Step(d *Device, now time.Time) bool {
+	changed := false
+	var done []func()
+	n := len(a.entries)
+	kept := 0
+	a.stepping = true
+	for i := 0; i < n; i++ {
+		// Index the slice each time, since a Step may add animations.
+		entry := a.entries[i]
+		if entry.animation.Step(d, now) {
+			changed = true
+		}
+		if entry.animation.Done() {
+			done = append(done, entry.done...)
+			continue
+		}
+		a.entries[kept] = entry
+		kept++
+	}
+	total := len(a.entries)
+	running := append(a.entries[:kept], a.entries[n:]...)
+	for i := len(running); i < total; i++ {
+		a.entries[i] = animatorEntry{}
+	}
+	a.entries = running
+	a.stepping = false
+	if a.stopping {
+		// An animation called Stop: it ends them now that the slice is whole.
+		a.stopping = false
+		a.Stop(d)
+		changed = true
+	}
+	// Called last, so that they can add animations and see the others done.
+	for _, f := range done {
+		f()
+	}
+	return changed
+}
+
+// Stop ends all animations at once, calling Stop on the ones that are
+// Stoppers, removes them and calls the functions registered with OnDone.
+//
+// When an animation calls it from Step, the animations are stopped once
+// the Step has stepped them all.
+//
+// Stopは、すべてのアニメーションをすぐに終え(Stopperであるものには
+// Stopを呼び出す)、取り除いてOnDoneで登録した関数を呼び出す。アニメー
+// ションがStepの中から呼び出した場合は、Stepがすべてを進め終えてから止める。
+func (a *Animator) Stop(d *Device) {
+	if a.stepping {
+		a.stopping = true
+		return
+	}
+	entries := a.entries
+	a.entries = nil
+	var done []func()
+	for _, entry := range entries {
+		if stopper, ok := entry.animation.(Stopper); ok {
+			stopper.Stop(d)
+		}
+		done = append(done, entry.done...)
+	}
+	for _, f := range done {
+		f()
+	}
+}
+
+// Len returns the number of running animations.
+//
+// Lenは、動いているアニメーションの数を返す。
+func (a *Animator) Len() int {
+	return len(a.entries)
+}
+
+// Animate starts an animation on the Device. Call Update from the main loop
+// to drive it.
+//
+// Animateは、Deviceでアニメーションを開始する。動かすにはメインループから
+// Updateを呼び出す。
+func (d *Device) Animate(animation Animation) {
+	d.lastAnimation = animation
+	d.animator.Add(d, animation, d.animationTime())
+}
+
+// OnDone calls f once the effect started last has finished, so that an
+// application can go on without polling IsFading and the like. It works for
+// every effect run by the Animator: StartFade, the transitions such as
+// WipeTo, StartFlash and the animations added with Animate; the endless
+// ones such as StartSnake finish when they are stopped. f is called from
+// Update, or at once if the effect has already finished.
+//
+// OnDoneは、最後に始めた効果が終わったときにfを呼び出すので、アプリケー
+// ションはIsFadingなどをポーリングせずに次へ進める。StartFade、WipeToなどの
+// トランジション、StartFlash、Animateで追加したアニメーションなど、Animator
+// が動かすすべての効果に使える。StartSnakeなどの終わりのないものは、止めた
+// ときに終わる。fはUpdateから呼び出し、効果が既に終わっていればすぐに呼び
+// 出す。
+func (d *Device) OnDone(f func()) {
+	if d.lastAnimation == nil || !d.animator.OnDone(d.lastAnimation, f) {
+		f()
+	}
+}
+
+// Update drives all non-blocking effects of the Device: the animations
+// added with Animate (including StartFade), the scrolling, the flips, the
+// separator blink, the digit blink, the cursor, the brightness dithering,
+// the emulated display brightness, the dimming schedule, the brightness
+// source and the screensaver. The display is sent at most once per effect
+// kind. It should be called frequently from the main loop, and returns true
+// while any effect is running.
+//
+// Updateは、Deviceのすべてのノンブロッキングの効果を動かす。Animateで追加
+// したアニメーション(StartFadeを含む)、スクロール、フリップ、区切りの点滅、
+// 桁の点滅、カーソル、明るさのディザリング、ディスプレイごとの明るさの
+// エミュレーション、減光スケジュール、明るさのソース、スクリーンセーバー。
+// ディスプレイは効果の種類ごとに多くとも1回だけ送る。メインループから
+// 頻繁に呼び出す必要があり、いずれかの効果が動いている間はtrueを返す。
+func (d *Device) Update() bool {
+	d.UpdateSchedule()
+	d.UpdateBrightnessSource()
+	d.UpdateIdle()
+	if !d.paused && d.animator.Step(d, d.animationTime()) {
+		d.Display()
+	}
+	scrolling := d.UpdateScroll()
+	flipping := d.UpdateFlip()
+	blinking := d.UpdateSeparatorBlink()
+	digitBlinking := d.UpdateBlink()
+	cursor := d.UpdateCursor()
+	dithering := d.UpdateDither()
+	duty := d.UpdateDuty()
+	return d.animator.Len() > 0 || scrolling || flipping || blinking || digitBlinking || cursor || dithering || duty
+}
+
+// StopAnimation ends every running effect at once and sends the final
+// content: the animations end as their Stop leaves them (a fade at the
+// brightness from before it, a transition on its new content, the endless
+// effects on the content they covered), the flips show their new content
+// and the scrolls stop on their current frame. The functions registered
+// with OnDone are called. The blinks and the cursor are not effects that
+// end, so they keep going.
+//
+// StopAnimationは、動いているすべての効果をすぐに終え、最終的な内容を送る。
+// アニメーションはStopが残す状態で終わり(フェードはフェード前の明るさ、
+// トランジションは新しい内容、終わりのない効果は覆っていた内容)、フリップは
+// 新しい内容を表示し、スクロールは現在のフレームで止まる。OnDoneで登録した
+// 関数を呼び出す。点滅とカーソルは終わる効果ではないので、動き続ける。
+func (d *Device) StopAnimation() {
+	d.animator.Stop(d)
+	for display := range d.flips {
+		if flip := &d.flips[display]; flip.active {
+			flip.active = false
+			d.drawFlip(display, flip)
+		}
+	}
+	for display := 0; display < d.displays; display++ {
+		d.StopScroll(display)
+	}
+	d.Display()
+}
+
+// IsAnimating returns true while any animation added with Animate is
+// running.
+//
+// IsAnimatingは、Animateで追加したアニメーションが動いている間はtrueを返す。
+func (d *Device) IsAnimating() bool {
+	return d.animator.Len() > 0
+}
+
+// Pause freezes the animations, the scrolls and the flips where they are,
+// for example while the application shows a menu over them. Update does
+// not move them until Resume, and animations started meanwhile wait too.
+// The blinks and the cursor keep going, as a menu may need them.
+//
+// Pauseは、アニメーション、スクロール、フリップをその場で止める。たとえば
+// アプリケーションがそれらの上にメニューを表示している間に使う。Resumeまで
+// Updateはそれらを動かさず、その間に始めたアニメーションも待つ。点滅と
+// カーソルはメニューで必要になることがあるので、動き続ける。
+func (d *Device) Pause() {
+	if d.paused {
+		return
+	}
+	d.paused = true
+	d.pausedAt = d.tick()
+}
+
+// Resume lets the effects frozen by Pause continue where they left off, as
+// if no time had passed while paused.
+//
+// Resumeは、Pauseで止めた効果を、止めている間に時間が経っていないかのように
+// 止めたところから続けさせる。
+func (d *Device) Resume() {
+	if !d.paused {
+		return
+	}
+	d.paused = false
+	paused := d.tick() - d.pausedAt
+	d.pausedTime += paused
+	for i := range d.scrolls {
+		d.scrolls[i].last += paused
+	}
+	for i := range d.flips {
+		d.flips[i].last += paused
+	}
+}
+
+// IsPaused returns true between Pause and Resume.
+//
+// IsPausedは、PauseからResumeまでの間はtrueを返す。
+func (d *Device) IsPaused() bool {
+	return d.paused
+}
+
+// animationTime returns the time the animations see: the current time less
+// the time spent paused, which stands still while paused.
+//
+// animationTimeは、アニメーションから見える時刻を返す。現在の時刻から
+// 止めていた時間を引いたもので、止めている間は進まない。
+func (d *Device) animationTime() time.Time {
+	now := d.now()
+	if d.paused {
+		now = now.Add(-d.sinceTick(d.pausedAt))
+	}
+	return now.Add(-tickDuration(d.pausedTime))
+}
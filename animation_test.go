@@ -0,0 +1,396 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// countAnimation lights one more digit on display A per step until it has lit n.
+type countAnimation struct {
+	n, steps int
+	started  bool
+	then     Animation // Added to the Device after the last step
+}
+
+func (c *countAnimation) Start(d *Device, now time.Time) {
+	c.started = true
+}
+
+func (c *countAnimation) Step(d *Device, now time.Time) bool {
+	d.setPattern(0, c.steps, segG, false)
+	c.steps++
+	if c.Done() && c.then != nil {
+		d.Animate(c.then)
+	}
+	return true
+}
+
+func (c *countAnimation) Done() bool {
+	return c.steps >= c.n
+}
+
+// TestAnimator verifies that animations are started, stepped and removed when done.
+func TestAnimator(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+
+	second := &countAnimation{n: 1}
+	first := &countAnimation{n: 2, then: second}
+	device.Animate(first)
+	if !first.started || !device.IsAnimating() {
+		t.Fatalf("FAIL: Animate() should start the animation")
+	}
+
+	device.Update()
+	assertDisplay(t, device, 0, "-")
+	if got := device.Stats().Transactions; got != 1 {
+		t.Errorf("FAIL: Update() should send the display once, got %d transactions", got)
+	}
+
+	// The first animation finishes and adds the second one.
+	device.Update()
+	if device.animator.Len() != 1 || !second.started {
+		t.Fatalf("FAIL: Animation added during Step should keep running, got %d animations", device.animator.Len())
+	}
+	if device.Update() {
+		t.Errorf("FAIL: Update() should return false after the last animation")
+	}
+	if second.steps != 1 {
+		t.Errorf("FAIL: Second animation should have stepped once, got %d", second.steps)
+	}
+}
+
+// TestUpdateFade verifies that Update drives the fade like UpdateFade.
+func TestUpdateFade(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetBrightness(3)
+	device.StartFade(0)
+	if !device.IsFading() || !device.IsAnimating() {
+		t.Fatalf("FAIL: StartFade() should add a running animation")
+	}
+	steps := 0
+	for device.Update() {
+		steps++
+	}
+	// 13 quarter steps down from 3 to 0, then 13 up from 0 to 3 again.
+	if steps != 25 || device.IsFading() || device.GetBrightness() != 3 {
+		t.Errorf("FAIL: Fade ended after %d steps at brightness %d", steps, device.GetBrightness())
+	}
+}
+
+// TestOnDone verifies that OnDone calls its function once the effect started last has finished.
+func TestOnDone(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	calls := 0
+	device.OnDone(func() { calls++ })
+	if calls != 1 {
+		t.Errorf("FAIL: OnDone() without an effect should call at once, got %d calls", calls)
+	}
+
+	device.WipeTo(0, "12", TransitionLeftToRight, 0)
+	device.OnDone(func() {
+		calls++
+		// A new effect can be started from the callback.
+		device.Animate(&countAnimation{n: 1})
+	})
+	for i := 0; i < 7; i++ {
+		device.Update()
+	}
+	if calls != 1 {
+		t.Errorf("FAIL: OnDone() should wait for the wipe, got %d calls", calls)
+	}
+	device.Update()
+	if calls != 2 || !device.IsAnimating() {
+		t.Errorf("FAIL: OnDone() should call once the wipe has finished, got %d calls", calls)
+	}
+	device.Update()
+	if calls != 2 {
+		t.Errorf("FAIL: OnDone() should call only once, got %d calls", calls)
+	}
+}
+
+// TestStopFade verifies that StopFade restores the brightness and sends the buffer.
+func TestStopFade(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.SetBrightness(9)
+	device.StartFade(0)
+	device.Update()
+	device.Update()
+	device.WriteString(0, "1")
+
+	device.StopFade()
+	if device.IsFading() || device.GetBrightness() != 9 {
+		t.Errorf("FAIL: StopFade() should end the fade at the brightness from before it, got %d", device.GetBrightness())
+	}
+	if mockBus.ram != device.buffer {
+		t.Errorf("FAIL: StopFade() should send the buffer, got %x", mockBus.ram)
+	}
+}
+
+// TestStopAnimation verifies that every effect jumps to its end and OnDone is called.
+func TestStopAnimation(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(1, "5")
+
+	device.WipeTo(0, "1234", TransitionLeftToRight, 0)
+	done := false
+	device.OnDone(func() { done = true })
+	device.StartSnake(1, 3, 0)
+	device.StartBreathing(time.Second)
+	device.Animate(&countAnimation{n: 5})
+	device.Update()
+
+	writes := mockBus.ramWrites
+	device.StopAnimation()
+	if device.IsAnimating() || device.IsBreathing() || !done {
+		t.Fatalf("FAIL: StopAnimation() should end every animation and call OnDone")
+	}
+	assertDisplay(t, device, 0, "1234")
+	assertDisplay(t, device, 1, "5")
+	if mockBus.ram != device.buffer {
+		t.Errorf("FAIL: StopAnimation() should send the final content, got %x", mockBus.ram)
+	}
+	if got := mockBus.ramWrites - writes; got != 1 {
+		t.Errorf("FAIL: StopAnimation() should send the buffer once, got %d writes", got)
+	}
+}
+
+// TestStopAnimationFromStep verifies that an animation can call StopAnimation from its Step.
+func TestStopAnimationFromStep(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+
+	other := &countAnimation{n: 5}
+	device.Animate(NewSequence(Action(func(d *Device) { d.StopAnimation() })))
+	device.Animate(other)
+	done := false
+	device.OnDone(func() { done = true })
+	device.Update()
+
+	if device.IsAnimating() || !done {
+		t.Fatalf("FAIL: StopAnimation() from a Step should end every animation, got %d animations", device.animator.Len())
+	}
+	if other.steps != 1 {
+		t.Errorf("FAIL: Animations after the stopping one should still step once, got %d steps", other.steps)
+	}
+}
+
+// TestPause verifies that Pause freezes the effects and Resume continues them without the paused time.
+func TestPause(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WipeTo(0, "12", TransitionLeftToRight, 0)
+	device.StartScroll(1, "123456789", time.Minute)
+	device.Update()
+
+	device.Pause()
+	device.Update()
+	device.Animate(&countAnimation{n: 1})
+	device.Update()
+	if !device.IsPaused() || device.animator.Len() != 2 {
+		t.Fatalf("FAIL: Update() should not step animations while paused, got %d", device.animator.Len())
+	}
+	assertDisplay(t, device, 0, "1")
+
+	device.Resume()
+	device.Update()
+	if device.IsPaused() || device.animator.Len() != 1 {
+		t.Errorf("FAIL: Update() should step animations after Resume(), got %d", device.animator.Len())
+	}
+	if got := device.digitPattern(0, 1); got == 0 {
+		t.Errorf("FAIL: Wipe should continue after Resume()")
+	}
+
+	// An hour of pause is not seen by the scroll waiting for a minute.
+	device.Pause()
+	advanceClock(device, time.Hour)
+	last := device.scrolls[1].last
+	device.Resume()
+	if got := tickDuration(device.scrolls[1].last - last); got < time.Hour {
+		t.Errorf("FAIL: Resume() should shift the scroll by the paused time, got %v", got)
+	}
+	if device.UpdateScroll(); device.scrolls[1].offset != 0 {
+		t.Errorf("FAIL: Scroll should not move after Resume(), got offset %d", device.scrolls[1].offset)
+	}
+	if now := device.animationTime(); device.now().Sub(now) < time.Hour {
+		t.Errorf("FAIL: Animations should not see the paused time, got %v", now)
+	}
+}
+
+// TestStartFadeRange verifies that the fade stays within its bounds and ends at the target.
+func TestStartFadeRange(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetBrightness(15)
+
+	device.StartFadeRange(0, 10, 2, 6)
+	var levels []uint8
+	for device.Update() {
+		levels = append(levels, device.GetBrightnessFine())
+	}
+	// Down from 15 limited to 10, to 2, then up to 6, in quarter steps.
+	var expected []uint8
+	for level := 10 * 4; level >= 2*4; level-- {
+		expected = append(expected, uint8(level))
+	}
+	for level := 2 * 4; level < 6*4; level++ {
+		expected = append(expected, uint8(level))
+	}
+	if len(levels) != len(expected) {
+		t.Fatalf("FAIL: Fade levels are wrong!\nExpected: %v\nGot:      %v", expected, levels)
+	}
+	for i := range expected {
+		if levels[i] != expected[i] {
+			t.Fatalf("FAIL: Fade levels are wrong!\nExpected: %v\nGot:      %v", expected, levels)
+		}
+	}
+	if got := device.GetBrightness(); got != 6 {
+		t.Errorf("FAIL: Fade should end at the target, got %d", got)
+	}
+	if got := device.FadeProgress(); got != 100 {
+		t.Errorf("FAIL: FadeProgress() = %d after fading, expected 100", got)
+	}
+}
+
+// TestStartFadeRangeBounds verifies that both bounds are limited to 15, in either order.
+func TestStartFadeRangeBounds(t *testing.T) {
+	tests := []struct {
+		min, max  uint8
+		low, high int
+	}{
+		{20, 16, 15, 15},
+		{18, 3, 3, 15},
+		{3, 18, 3, 15},
+	}
+	for _, tt := range tests {
+		device := newTestDevice(t, &mockI2C{})
+		device.SetBrightness(8)
+		device.StartFadeRange(0, tt.min, tt.max, 8)
+		if device.fade.low != tt.low*4 || device.fade.high != tt.high*4 {
+			t.Errorf("FAIL: StartFadeRange(%d, %d) range is wrong!\nExpected: %d-%d\nGot:      %d-%d",
+				tt.min, tt.max, tt.low, tt.high, device.fade.low/4, device.fade.high/4)
+		}
+		for device.Update() {
+			if got := device.GetBrightness(); got < uint8(tt.low) || got > uint8(tt.high) {
+				t.Fatalf("FAIL: StartFadeRange(%d, %d) left the range, got %d", tt.min, tt.max, got)
+			}
+		}
+		if got := device.FadeProgress(); got != 100 {
+			t.Errorf("FAIL: FadeProgress() = %d after fading, expected 100", got)
+		}
+	}
+}
+
+// TestStartFadeOutIn verifies that fading out keeps the content and fading in sends it and restores the brightness.
+func TestStartFadeOutIn(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.SetBrightness(5)
+
+	device.StartFadeOut(0)
+	for device.Update() {
+	}
+	if device.GetBrightness() != 0 || mockBus.ram != [16]byte{} {
+		t.Fatalf("FAIL: StartFadeOut() should end dark without sending, got %d", device.GetBrightness())
+	}
+
+	// Compose the next screen in the dark.
+	device.WriteString(0, "1")
+	device.StartFadeIn(0)
+	if mockBus.ram != device.buffer {
+		t.Errorf("FAIL: StartFadeIn() should send the buffer, got %x", mockBus.ram)
+	}
+	if got := device.FadeProgress(); got != 0 {
+		t.Errorf("FAIL: FadeProgress() = %d at the start, expected 0", got)
+	}
+	steps := 0
+	for device.Update() {
+		steps++
+	}
+	if steps != 5*4 || device.GetBrightness() != 5 {
+		t.Errorf("FAIL: StartFadeIn() should go up to 5 again, got %d after %d steps", device.GetBrightness(), steps)
+	}
+
+	// Without a fade-out, the fade-in goes up to the current brightness.
+	device.StartFadeIn(0)
+	for device.Update() {
+	}
+	if got := device.GetBrightness(); got != 5 {
+		t.Errorf("FAIL: StartFadeIn() should end at the current brightness, got %d", got)
+	}
+}
+
+// TestStartLightUpAllFade verifies that all segments are sent and the brightness goes up to 15.
+func TestStartLightUpAllFade(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.SetBrightness(3)
+
+	device.StartLightUpAllFade(0)
+	if !device.IsFading() || device.GetBrightness() != 0 {
+		t.Fatalf("FAIL: StartLightUpAllFade() should start a fade from 0")
+	}
+	for _, row := range mockBus.ram {
+		if row != 0xFF {
+			t.Fatalf("FAIL: All segments should be sent, got %x", mockBus.ram)
+		}
+	}
+	steps := 0
+	for device.UpdateFade() {
+		steps++
+	}
+	if steps != 15*4 || device.GetBrightness() != 15 {
+		t.Errorf("FAIL: Fade should go from 0 up to 15, got %d after %d steps", device.GetBrightness(), steps)
+	}
+}
+
+// TestUpdateFadeElapsed verifies that the fade steps by the elapsed time given to it.
+func TestUpdateFadeElapsed(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.SetBrightness(4)
+	device.StartFade(10 * time.Millisecond)
+
+	// A quarter step takes 2.5ms: part of one does nothing, and the rest
+	// takes it, keeping the fraction for the next.
+	device.UpdateFadeElapsed(2 * time.Millisecond)
+	if got := device.GetBrightnessFine(); got != 16 {
+		t.Errorf("FAIL: Part of a step should not change the brightness, got %d", got)
+	}
+	device.UpdateFadeElapsed(3 * time.Millisecond)
+	device.UpdateFadeElapsed(20 * time.Millisecond)
+	if got := device.GetBrightnessFine(); got != 7 {
+		t.Errorf("FAIL: Brightness after 10 quarter steps is wrong!\nExpected: %d\nGot:      %d", 7, got)
+	}
+
+	device.WriteString(0, "1")
+	if device.UpdateFadeElapsed(time.Second) || device.GetBrightness() != 4 {
+		t.Errorf("FAIL: A long dt should finish the fade, got %d", device.GetBrightness())
+	}
+	if mockBus.ram != device.buffer {
+		t.Errorf("FAIL: The content should be sent when faded out, got %x", mockBus.ram)
+	}
+}
+
+// TestFadeShortDelay verifies that a delay shorter than a millisecond tick still paces the fade.
+func TestFadeShortDelay(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetBrightness(4)
+	device.StartFade(500 * time.Microsecond)
+
+	// Without the clock moving, the fade does not step.
+	for i := 0; i < 5; i++ {
+		device.Update()
+	}
+	if got := device.GetBrightness(); got != 4 {
+		t.Errorf("FAIL: Fade should wait for the delay, got brightness %d", got)
+	}
+	for i := 0; i < 3; i++ {
+		advanceClock(device, time.Millisecond)
+		device.Update()
+	}
+	if got := device.GetBrightnessFine(); got != 14 {
+		t.Errorf("FAIL: Brightness after 3 quarter steps is wrong!\nExpected: %d\nGot:      %d", 14, got)
+	}
+}
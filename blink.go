@@ -0,0 +1,81 @@
+package ht16k33
+
+import "time"
+
+// defaultBlinkPeriod is the period of the digit blink until SetBlinkRate.
+const defaultBlinkPeriod = time.Second
+
+// SetBlink marks a digit as blinking, or steady again. All blinking digits
+// share one phase and rate (see SetBlinkRate): they are shown for the first
+// half of each period and hidden for the second half, while the other
+// digits stay steady. Their content can be changed as usual while they
+// blink. It is non-blocking: call Update (or UpdateBlink) from the main
+// loop.
+//
+// SetBlinkは、桁を点滅するように、または再び点灯したままにするように設定
+// する。点滅するすべての桁は1つの位相と周期(SetBlinkRateを参照)を共有し、
+// 各周期の前半に表示して後半に消し、その他の桁は点灯したままになる。
+// 点滅中も内容は通常どおり変更できる。ノンブロッキングなので、メインループ
+// からUpdate(またはUpdateBlink)を呼び出す。
+func (d *Device) SetBlink(display int, position int, blink bool) error {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits {
+		return ErrInvalidDisplay
+	}
+	if !d.isBlinking() {
+		d.digitBlinkStart = d.tick()
+	}
+	if blink {
+		d.blinkDigits[display] |= 1 << position
+	} else {
+		d.blinkDigits[display] &^= 1 << position
+	}
+	if !d.isBlinking() && d.digitsOff {
+		d.digitsOff = false
+		d.Display()
+	}
+	return nil
+}
+
+// ClearBlink makes all blinking digits steady again.
+//
+// ClearBlinkは、点滅しているすべての桁を再び点灯したままにする。
+func (d *Device) ClearBlink() {
+	d.blinkDigits = [NumDisplays]byte{}
+	if d.digitsOff {
+		d.digitsOff = false
+		d.Display()
+	}
+}
+
+// SetBlinkRate sets the period of the digit blink; 0 means one second.
+//
+// SetBlinkRateは、桁の点滅の周期を設定する。0なら1秒になる。
+func (d *Device) SetBlinkRate(period time.Duration) {
+	d.blinkPeriod = delayTicks(period)
+}
+
+// UpdateBlink drives the digit blink set by SetBlink, sending the display
+// only when the blinking digits are shown or hidden. It returns true while
+// any digit blinks.
+//
+// UpdateBlinkは、SetBlinkで設定した桁の点滅を動かし、点滅する桁を表示または
+// 消すときだけディスプレイに送る。いずれかの桁が点滅している間はtrueを返す。
+func (d *Device) UpdateBlink() bool {
+	if !d.isBlinking() {
+		return false
+	}
+	period := tickInterval(d.blinkPeriod, defaultBlinkPeriod)
+	off := (d.tick()-d.digitBlinkStart)%period >= period/2
+	if off != d.digitsOff {
+		d.digitsOff = off
+		d.Display()
+	}
+	return true
+}
+
+// isBlinking returns true if any digit is marked as blinking.
+//
+// isBlinkingは、点滅する桁があればtrueを返す。
+func (d *Device) isBlinking() bool {
+	return d.blinkDigits != [NumDisplays]byte{}
+}
@@ -0,0 +1,43 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSetBlink verifies that only the marked digits are hidden in the second half of the period.
+func TestSetBlink(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "12")
+	device.WriteString(1, "3")
+	device.SetBlinkRate(time.Hour)
+
+	device.Display()
+	device.SetBlink(0, 1, true)
+	device.SetBlink(1, 0, true)
+	if !device.UpdateBlink() || mockBus.ram != device.buffer {
+		t.Fatalf("FAIL: Blinking digits should be shown in the first half, got %x", mockBus.ram)
+	}
+
+	advanceClock(device, time.Hour/2)
+	device.Update()
+	expected := device.buffer
+	for row := 0; row < segmentRows; row++ {
+		expected[row] &^= 1 << 1
+		expected[segmentRows+row] = 0
+	}
+	if mockBus.ram != expected {
+		t.Errorf("FAIL: Blinking digits should be hidden in the second half!\nExpected: %x\nGot:      %x", expected, mockBus.ram)
+	}
+
+	device.SetBlink(0, 1, false)
+	device.ClearBlink()
+	if device.UpdateBlink() || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: ClearBlink() should show every digit again, got %x", mockBus.ram)
+	}
+
+	if err := device.SetBlink(0, 8, true); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: SetBlink() with an invalid position should return ErrInvalidDisplay, got %v", err)
+	}
+}
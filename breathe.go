@@ -0,0 +1,83 @@
+package ht16k33
+
+import (
+	"math"
+	"time"
+)
+
+// breathAnimation is the Animation started by StartBreathing.
+//
+// breathAnimationは、StartBreathingで始めるAnimation。
+type breathAnimation struct {
+	period time.Duration
+	// peak is the brightness at the top of each breath, restored when the
+	// breathing stops.
+	// peakは各呼吸の頂点の明るさで、呼吸を止めたときに元に戻す。
+	peak    uint8
+	start   uint32
+	stopped bool
+}
+
+// Start begins the first breath at the peak brightness.
+func (b *breathAnimation) Start(d *Device, now time.Time) {
+	b.peak = d.currentBrightness
+	b.start = tickOf(now)
+}
+
+// Step sets the brightness on the sine curve, sending it only when the
+// level changes.
+func (b *breathAnimation) Step(d *Device, now time.Time) bool {
+	if b.stopped || b.period <= 0 {
+		return false
+	}
+	phase := float64(tickDuration(tickOf(now)-b.start)%b.period) / float64(b.period)
+	level := uint8(math.Round(float64(b.peak) * (1 + math.Cos(2*math.Pi*phase)) / 2))
+	if level != d.currentBrightness {
+		d.SetBrightness(level)
+	}
+	return false
+}
+
+// Done reports whether the breathing was stopped.
+func (b *breathAnimation) Done() bool {
+	return b.stopped
+}
+
+// Stop stops the breathing as StopBreathing does.
+func (b *breathAnimation) Stop(d *Device) {
+	d.StopBreathing()
+}
+
+// StartBreathing makes the brightness breathe, for standby and idle
+// indication: it follows a sine curve from the current brightness down to 0
+// and back up once per period, until StopBreathing. It is non-blocking:
+// call Update from the main loop.
+//
+// StartBreathingは、待機中やアイドルの表示のために明るさを呼吸させる。明るさ
+// はStopBreathingまで、周期ごとに現在の明るさから0まで下がって戻る正弦曲線
+// に従う。ノンブロッキングなので、メインループからUpdateを呼び出す。
+func (d *Device) StartBreathing(period time.Duration) {
+	d.StopBreathing()
+	d.breath = &breathAnimation{period: period}
+	d.Animate(d.breath)
+}
+
+// StopBreathing stops the breathing and restores the brightness it started
+// from.
+//
+// StopBreathingは、呼吸を止めて始めたときの明るさに戻す。
+func (d *Device) StopBreathing() {
+	if d.breath == nil {
+		return
+	}
+	d.breath.stopped = true
+	d.SetBrightness(d.breath.peak)
+	d.breath = nil
+}
+
+// IsBreathing returns true while the brightness breathes.
+//
+// IsBreathingは、明るさが呼吸している間はtrueを返す。
+func (d *Device) IsBreathing() bool {
+	return d.breath != nil
+}
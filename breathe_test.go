@@ -0,0 +1,40 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestStartBreathing verifies the brightness along the curve and that StopBreathing restores it.
+func TestStartBreathing(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.SetBrightness(10)
+
+	device.StartBreathing(4 * time.Second)
+	start := device.animationTime()
+	expected := []struct {
+		at         time.Duration
+		brightness uint8
+	}{
+		{0, 10},
+		{time.Second, 5},
+		{2 * time.Second, 0},
+		{3 * time.Second, 5},
+		{5 * time.Second, 5},
+	}
+	for _, tt := range expected {
+		device.animator.Step(device, start.Add(tt.at))
+		if got := device.GetBrightness(); got != tt.brightness {
+			t.Errorf("FAIL: Brightness after %v is wrong!\nExpected: %d\nGot:      %d", tt.at, tt.brightness, got)
+		}
+	}
+
+	device.StopBreathing()
+	if device.IsBreathing() || device.GetBrightness() != 10 || mockBus.data[0] != ht16k33SetBrightness|10 {
+		t.Errorf("FAIL: StopBreathing() should restore the brightness, got %d", device.GetBrightness())
+	}
+	if device.Update() {
+		t.Errorf("FAIL: Breathing should stop")
+	}
+}
@@ -0,0 +1,247 @@
+package ht16k33
+
+import "time"
+
+// rampAnimation is the Animation started by SetBrightnessSmooth. Its levels
+// are the quarter steps of SetBrightnessFine.
+//
+// rampAnimationは、SetBrightnessSmoothで始めるAnimation。レベルは
+// SetBrightnessFineの1/4段階。
+type rampAnimation struct {
+	from, to int
+	duration time.Duration
+	start    uint32
+	done     bool
+}
+
+// Start begins the ramp at the current brightness.
+func (r *rampAnimation) Start(d *Device, now time.Time) {
+	r.from = int(d.GetBrightnessFine())
+	r.start = tickOf(now)
+}
+
+// Step sets the brightness on the straight line from the start to the
+// target, changing it only when the quarter step changes.
+func (r *rampAnimation) Step(d *Device, now time.Time) bool {
+	if r.done {
+		return false
+	}
+	level := r.to
+	if elapsed := tickDuration(tickOf(now) - r.start); elapsed < r.duration {
+		level = r.from + int((int64(r.to-r.from)*int64(elapsed)+int64(r.duration)/2)/int64(r.duration))
+	} else {
+		r.done = true
+	}
+	if uint8(level) != d.GetBrightnessFine() {
+		d.SetBrightnessFine(uint8(level))
+	}
+	return false
+}
+
+// Done reports whether the target has been reached.
+func (r *rampAnimation) Done() bool {
+	return r.done
+}
+
+// Stop sets the target brightness at once.
+func (r *rampAnimation) Stop(d *Device) {
+	if !r.done {
+		r.done = true
+		d.SetBrightnessFine(uint8(r.to))
+	}
+}
+
+// SetBrightnessSmooth changes the brightness to target (0-15) gradually
+// over duration instead of at once, for example when an ambient light
+// sensor drives it. It goes through the quarter steps of SetBrightnessFine.
+// It replaces a ramp that is still running. It is non-blocking: call
+// Update from the main loop.
+//
+// SetBrightnessSmoothは、明るさをすぐにではなくdurationをかけて徐々に
+// target(0-15)に変える。たとえば環境光センサーで明るさを変える場合に使う。
+// SetBrightnessFineの1/4段階を通る。まだ動いている変化を置き換える。ノン
+// ブロッキングなので、メインループからUpdateを呼び出す。
+func (d *Device) SetBrightnessSmooth(target uint8, duration time.Duration) {
+	if d.ramp != nil {
+		d.ramp.done = true
+	}
+	d.ramp = &rampAnimation{to: int(min(target, 15)) * 4, duration: duration}
+	d.Animate(d.ramp)
+}
+
+// MaxFineBrightness is the highest level of SetBrightnessFine: a quarter
+// step for each of the 15 steps of the chip.
+//
+// MaxFineBrightnessは、SetBrightnessFineの最大のレベル。チップの15段階の
+// それぞれを4分割する。
+const MaxFineBrightness = 60
+
+// SetBrightnessFine sets the brightness in quarter steps (0-60), so that
+// fades can be smoother than the 16 levels of the chip. A level between
+// two chip levels is shown by alternating them from Update (temporal
+// dithering), so Update should be called at least every few milliseconds to
+// avoid a visible flicker. SetBrightness ends the dithering.
+//
+// SetBrightnessFineは、明るさを1/4段階(0-60)で設定するので、チップの16段階
+// より滑らかにフェードできる。チップの2つの段階の間のレベルは、Updateから
+// それらを交互に切り替えて(時間的ディザリング)表示するので、ちらつきが
+// 見えないように、Updateを少なくとも数ミリ秒ごとに呼び出す必要がある。
+// SetBrightnessはディザリングを終える。
+func (d *Device) SetBrightnessFine(level uint8) {
+	level = min(level, MaxFineBrightness)
+	d.SetBrightness(level / 4)
+	d.ditherBase = level / 4
+	d.ditherFraction = int(level % 4)
+	d.ditherError = 0
+}
+
+// GetBrightnessFine returns the brightness in quarter steps (0-60), as set
+// by SetBrightnessFine or four times the level of SetBrightness.
+//
+// GetBrightnessFineは、明るさを1/4段階(0-60)で返す。SetBrightnessFineで
+// 設定した値か、SetBrightnessのレベルの4倍になる。
+func (d *Device) GetBrightnessFine() uint8 {
+	if d.ditherFraction != 0 {
+		return d.ditherBase*4 + uint8(d.ditherFraction)
+	}
+	return d.currentBrightness * 4
+}
+
+// UpdateDither drives the dithering of SetBrightnessFine, sending the
+// brightness only when it changes. It returns true while dithering.
+//
+// UpdateDitherは、SetBrightnessFineのディザリングを動かし、明るさが変わる
+// ときだけ送る。ディザリングしている間はtrueを返す。
+func (d *Device) UpdateDither() bool {
+	if d.ditherFraction == 0 {
+		return false
+	}
+	level := d.ditherBase
+	if d.ditherError += d.ditherFraction; d.ditherError >= 4 {
+		d.ditherError -= 4
+		level++
+	}
+	if level != d.currentBrightness {
+		d.sendBrightness(level)
+	}
+	return true
+}
+
+const (
+	// sourceInterval is how often the brightness source is read.
+	sourceInterval = 100 * time.Millisecond
+	// sourceSmoothing is the number of readings the average mostly follows.
+	sourceSmoothing = 16
+	// sourceStep is the span of readings for one brightness level, and
+	// sourceHysteresis how far the average has to go past it to change the
+	// level, so that a reading near a boundary does not flicker.
+	sourceStep       = 65536 / 16
+	sourceHysteresis = sourceStep / 4
+	// sourceRamp is how long the brightness takes to change to a new level.
+	sourceRamp = 500 * time.Millisecond
+)
+
+// SetBrightnessSource sets a function, for example one reading an LDR or a
+// lux sensor, whose value (0-65535) drives the brightness (0-15) from Update
+// (or UpdateBrightnessSource). The readings are averaged, and the level
+// changes smoothly only when the average goes clearly past it, so a noisy
+// sensor does not make the display flicker. nil stops it, leaving the
+// brightness as it is.
+//
+// SetBrightnessSourceは、値(0-65535)がUpdate(またはUpdateBrightnessSource)
+// から明るさ(0-15)を決める関数を設定する。たとえばLDRや照度センサーを読む
+// 関数。読み取り値は平均し、平均がレベルをはっきり越えたときだけレベルを
+// 滑らかに変えるので、ノイズの多いセンサーでもディスプレイはちらつかない。
+// nilなら止め、明るさはそのままにする。
+func (d *Device) SetBrightnessSource(source func() uint16) {
+	d.brightnessSource = source
+	d.sourceLevel = -1
+}
+
+// UpdateBrightnessSource reads the brightness source at most every 100ms
+// and starts the change of brightness when its level has changed. It
+// returns true when it has started one.
+//
+// UpdateBrightnessSourceは、明るさのソースを多くとも100msごとに読み、その
+// レベルが変わったときに明るさの変化を始める。変化を始めたときはtrueを返す。
+func (d *Device) UpdateBrightnessSource() bool {
+	if d.brightnessSource == nil {
+		return false
+	}
+	now := d.tick()
+	if d.sourceLevel >= 0 && tickDuration(now-d.sourceLast) < sourceInterval {
+		return false
+	}
+	d.sourceLast = now
+	reading := int(d.brightnessSource())
+	if d.sourceLevel < 0 {
+		d.sourceAverage = reading
+		d.sourceLevel = reading / sourceStep
+		d.SetBrightness(uint8(d.sourceLevel))
+		return false
+	}
+	d.sourceAverage += (reading - d.sourceAverage) / sourceSmoothing
+	low := d.sourceLevel*sourceStep - sourceHysteresis
+	high := (d.sourceLevel+1)*sourceStep + sourceHysteresis
+	if d.sourceAverage >= low && d.sourceAverage < high {
+		return false
+	}
+	d.sourceLevel = d.sourceAverage / sourceStep
+	d.SetBrightnessSmooth(uint8(d.sourceLevel), sourceRamp)
+	return true
+}
+
+// SetDisplayBrightness sets the brightness (0-15) of one display relative
+// to the brightness of the chip, which is shared by both displays: below 15
+// the display is turned off for a part of the Updates (duty-cycling), so it
+// looks dimmer, for example to match displays behind different diffusers.
+// Update (or UpdateDuty) should be called at least every few milliseconds
+// to avoid a visible flicker.
+//
+// SetDisplayBrightnessは、両方のディスプレイで共有するチップの明るさに
+// 対する、1つのディスプレイの明るさ(0-15)を設定する。15未満ではUpdateの
+// 一部でディスプレイを消す(デューティサイクル)ので暗く見え、たとえば
+// 拡散板の違うディスプレイを揃えられる。ちらつきが見えないように、Update
+// (またはUpdateDuty)を少なくとも数ミリ秒ごとに呼び出す必要がある。
+func (d *Device) SetDisplayBrightness(display int, brightness uint8) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	d.displayDim[display] = 15 - min(brightness, 15)
+	d.dutyError[display] = 0
+	if d.displayDim[display] == 0 && d.dutyOff&(1<<display) != 0 {
+		d.dutyOff &^= 1 << display
+		d.Display()
+	}
+	return nil
+}
+
+// UpdateDuty drives the duty-cycling of SetDisplayBrightness, sending the
+// display only when a display is turned on or off. It returns true while
+// any display is dimmed.
+//
+// UpdateDutyは、SetDisplayBrightnessのデューティサイクルを動かし、
+// ディスプレイを点けるか消すときだけ送る。いずれかのディスプレイを暗く
+// している間はtrueを返す。
+func (d *Device) UpdateDuty() bool {
+	if d.displayDim == [NumDisplays]uint8{} {
+		return false
+	}
+	off := byte(0)
+	for display, dim := range d.displayDim {
+		if dim == 0 {
+			continue
+		}
+		// Shown in 16-dim out of every 16 Updates, spread evenly.
+		if d.dutyError[display] += 16 - int(dim); d.dutyError[display] >= 16 {
+			d.dutyError[display] -= 16
+		} else {
+			off |= 1 << display
+		}
+	}
+	if off != d.dutyOff {
+		d.dutyOff = off
+		d.Display()
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSetBrightnessSmooth verifies that the brightness ramps to the target over the duration.
+func TestSetBrightnessSmooth(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetBrightness(2)
+
+	device.SetBrightnessSmooth(12, time.Second)
+	start := device.animationTime()
+	expected := []struct {
+		at         time.Duration
+		brightness uint8
+	}{
+		// In quarter steps, 2.5 levels every 250ms, between chip levels.
+		{0, 2 * 4},
+		{250 * time.Millisecond, 18},
+		{500 * time.Millisecond, 7 * 4},
+		{time.Second, 12 * 4},
+	}
+	for _, tt := range expected {
+		device.animator.Step(device, start.Add(tt.at))
+		if got := device.GetBrightnessFine(); got != tt.brightness {
+			t.Errorf("FAIL: Brightness after %v is wrong!\nExpected: %d\nGot:      %d", tt.at, tt.brightness, got)
+		}
+	}
+	if device.IsAnimating() {
+		t.Errorf("FAIL: Ramp should finish at the target")
+	}
+
+	// A new ramp replaces the running one, and down works as well.
+	device.SetBrightnessSmooth(0, time.Second)
+	device.SetBrightnessSmooth(20, 0)
+	device.Update()
+	if got := device.GetBrightness(); got != 15 || device.IsAnimating() {
+		t.Errorf("FAIL: Ramp should be replaced and limited to 15, got %d", got)
+	}
+}
+
+// TestSetBrightnessFine verifies that a quarter level alternates the chip levels around it.
+func TestSetBrightnessFine(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	device.SetBrightnessFine(29)
+	if got := device.GetBrightnessFine(); got != 29 {
+		t.Errorf("FAIL: GetBrightnessFine() is wrong!\nExpected: %d\nGot:      %d", 29, got)
+	}
+	// 29 is 7 and a quarter: one Update in four shows 8.
+	var levels []uint8
+	for i := 0; i < 8; i++ {
+		if !device.Update() {
+			t.Fatalf("FAIL: Update() should return true while dithering")
+		}
+		levels = append(levels, device.GetBrightness())
+	}
+	expected := []uint8{7, 7, 7, 8, 7, 7, 7, 8}
+	for i := range expected {
+		if levels[i] != expected[i] {
+			t.Fatalf("FAIL: Dithered levels are wrong!\nExpected: %v\nGot:      %v", expected, levels)
+		}
+	}
+
+	// A whole level and SetBrightness do not dither.
+	device.SetBrightnessFine(255)
+	if device.Update() || device.GetBrightness() != 15 {
+		t.Errorf("FAIL: Level 60 should be 15 without dithering, got %d", device.GetBrightness())
+	}
+	device.SetBrightnessFine(2)
+	device.SetBrightness(4)
+	if device.Update() || device.GetBrightnessFine() != 16 {
+		t.Errorf("FAIL: SetBrightness() should end the dithering, got %d", device.GetBrightnessFine())
+	}
+}
+
+// TestSetBrightnessSource verifies that the readings are smoothed and a level changes only past the hysteresis.
+func TestSetBrightnessSource(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	reading := uint16(5 * sourceStep)
+	device.SetBrightnessSource(func() uint16 { return reading })
+
+	// The first reading sets the level at once.
+	device.UpdateBrightnessSource()
+	if got := device.GetBrightness(); got != 5 {
+		t.Fatalf("FAIL: First reading should set the brightness!\nExpected: %d\nGot:      %d", 5, got)
+	}
+
+	read := func() bool {
+		advanceClock(device, sourceInterval)
+		return device.UpdateBrightnessSource()
+	}
+	// Just past the boundary to 6 is within the hysteresis.
+	reading = 6*sourceStep + sourceHysteresis/2
+	for i := 0; i < 50; i++ {
+		if read() {
+			t.Fatalf("FAIL: Reading within the hysteresis should not change the level")
+		}
+	}
+	// A single bright reading is smoothed away.
+	reading = 5*sourceStep + sourceStep/2
+	for i := 0; i < 50; i++ {
+		read()
+	}
+	reading = 0xFFFF
+	if read() {
+		t.Errorf("FAIL: A single reading should not change the level")
+	}
+	// A lasting change gets there.
+	reading = 10*sourceStep + sourceStep/2
+	for i := 0; i < 100; i++ {
+		read()
+	}
+	device.StopAnimation()
+	if got := device.GetBrightness(); got != 10 {
+		t.Errorf("FAIL: A lasting change should change the level!\nExpected: %d\nGot:      %d", 10, got)
+	}
+
+	device.SetBrightnessSource(nil)
+	if device.UpdateBrightnessSource() {
+		t.Errorf("FAIL: SetBrightnessSource(nil) should stop it")
+	}
+}
+
+// TestSetDisplayBrightness verifies that a dimmed display is shown in its share of the Updates only.
+func TestSetDisplayBrightness(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "1")
+	device.WriteString(1, "2")
+
+	if err := device.SetDisplayBrightness(1, 11); err != nil {
+		t.Fatalf("SetDisplayBrightness() returned an unexpected error: %v", err)
+	}
+	shownA, shownB := 0, 0
+	for i := 0; i < 16; i++ {
+		if !device.Update() {
+			t.Fatalf("FAIL: Update() should return true while a display is dimmed")
+		}
+		if mockBus.ram[0] != 0 || mockBus.ram[1] != 0 {
+			shownA++
+		}
+		if mockBus.ram[segmentRows] != 0 || mockBus.ram[segmentRows+1] != 0 {
+			shownB++
+		}
+	}
+	if shownA != 16 || shownB != 12 {
+		t.Errorf("FAIL: Displays were shown in %d and %d of 16 Updates, expected 16 and 12", shownA, shownB)
+	}
+
+	// Full brightness shows the display again at once.
+	device.SetDisplayBrightness(1, 15)
+	if device.Update() || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: SetDisplayBrightness(15) should end the duty-cycling, got %x", mockBus.ram)
+	}
+	if err := device.SetDisplayBrightness(2, 0); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: SetDisplayBrightness() on display 2 should return ErrInvalidDisplay, got %v", err)
+	}
+}
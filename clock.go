@@ -0,0 +1,87 @@
+package ht16k33
+
+import "time"
+
+// Clock gives the current time to the non-blocking effects of the Device.
+// Tests can pass one that steps virtual time with WithClock to check the
+// effects deterministically instead of sleeping.
+//
+// Clockは、Deviceのノンブロッキングの効果に現在時刻を与える。テストでは
+// 仮想時間を進めるものをWithClockで渡せば、スリープせずに効果を決定的に
+// 確認できる。
+type Clock interface {
+	Now() time.Time
+}
+
+// now returns the current time of the clock, or time.Now without one.
+//
+// nowは、時計の現在時刻を返す。時計がなければtime.Nowになる。
+func (d *Device) now() time.Time {
+	if d.clock != nil {
+		return d.clock.Now()
+	}
+	return time.Now()
+}
+
+// tickOf returns the time t in millisecond ticks. The Device keeps its
+// times as ticks, which take 4 bytes instead of the 24 of a time.Time. They
+// wrap around after about 49 days, so only their differences are used.
+//
+// tickOfは、時刻tをミリ秒単位のティックで返す。Deviceは時刻をティックで
+// 保持し、time.Timeの24バイトではなく4バイトで済む。約49日で一周するので、
+// 差だけを使う。
+func tickOf(t time.Time) uint32 {
+	return uint32(t.UnixMilli())
+}
+
+// ticks converts a duration to millisecond ticks.
+//
+// ticksは、時間をミリ秒単位のティックに変換する。
+func ticks(d time.Duration) uint32 {
+	return uint32(d / time.Millisecond)
+}
+
+// delayTicks converts a delay or a period set by the application to
+// millisecond ticks. One shorter than a tick is rounded up to one, so that
+// it still paces the effect rather than meaning none or the default.
+//
+// delayTicksは、アプリケーションが設定した遅延や周期をミリ秒単位のティック
+// に変換する。1ティックより短いものは1に切り上げるので、なしやデフォルトの
+// 意味にならずに効果の速さを保つ。
+func delayTicks(d time.Duration) uint32 {
+	if d > 0 && d < time.Millisecond {
+		return 1
+	}
+	return ticks(max(d, 0))
+}
+
+// tickInterval returns v, or the ticks of def if v is 0.
+//
+// tickIntervalは、vを返す。vが0ならdefのティックを返す。
+func tickInterval(v uint32, def time.Duration) uint32 {
+	if v == 0 {
+		return ticks(def)
+	}
+	return v
+}
+
+// tickDuration converts millisecond ticks to a duration.
+//
+// tickDurationは、ミリ秒単位のティックを時間に変換する。
+func tickDuration(t uint32) time.Duration {
+	return time.Duration(t) * time.Millisecond
+}
+
+// tick returns the current time of the clock in millisecond ticks.
+//
+// tickは、時計の現在時刻をミリ秒単位のティックで返す。
+func (d *Device) tick() uint32 {
+	return tickOf(d.now())
+}
+
+// sinceTick returns the time elapsed on the clock since the tick t.
+//
+// sinceTickは、ティックtから時計で経過した時間を返す。
+func (d *Device) sinceTick(t uint32) time.Duration {
+	return tickDuration(d.tick() - t)
+}
@@ -0,0 +1,58 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock whose time only moves with advance.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// testTime is the time the fakeClock of newTestDevice starts at.
+var testTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// advanceClock moves the fakeClock of a Device from newTestDevice.
+func advanceClock(device *Device, d time.Duration) {
+	device.clock.(*fakeClock).advance(d)
+}
+
+// TestWithClock verifies that the fade and the scroll follow the virtual time of the Clock.
+func TestWithClock(t *testing.T) {
+	clock := &fakeClock{now: testTime}
+	device, err := New(&mockI2C{}, 0x70, WithClock(clock))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	device.SetBrightness(2)
+	device.StartFade(time.Second)
+	device.StartScroll(1, "123456789", time.Second)
+
+	// Without time passing, nothing moves.
+	for i := 0; i < 5; i++ {
+		device.Update()
+	}
+	if device.GetBrightness() != 2 || device.scrolls[1].offset != 0 {
+		t.Fatalf("FAIL: Effects should wait for the clock, got brightness %d", device.GetBrightness())
+	}
+
+	clock.advance(time.Second)
+	device.Update()
+	if device.GetBrightness() != 2 || device.scrolls[1].offset != 1 {
+		t.Errorf("FAIL: One second should take one step, got brightness %d and offset %d", device.GetBrightness(), device.scrolls[1].offset)
+	}
+	clock.advance(time.Second)
+	device.Update()
+	if got := device.GetBrightness(); got != 1 {
+		t.Errorf("FAIL: Brightness after two steps is wrong!\nExpected: %d\nGot:      %d", 1, got)
+	}
+}
@@ -0,0 +1,89 @@
+package ht16k33
+
+import "time"
+
+// defaultCursorPeriod is the period of the cursor until SetCursorRate.
+const defaultCursorPeriod = time.Second
+
+// cursorPattern is what the cursor shows in place of the digit: an
+// underscore.
+const cursorPattern = segD
+
+// SetCursor shows an edit cursor on a digit, for set-time and set-value
+// screens: the digit shows its content for the first half of each period
+// (see SetCursorRate) and an underscore for the second half. There is one
+// cursor, so this moves it, and the phase starts again so that it stays
+// readable while it moves. The content of the digit can be changed as
+// usual. It is non-blocking: call Update (or UpdateCursor) from the main
+// loop.
+//
+// SetCursorは、時刻や値の設定画面のために桁に編集カーソルを表示する。桁は
+// 各周期(SetCursorRateを参照)の前半に内容を、後半にアンダースコアを表示する。
+// カーソルは1つなのでこれで移動し、移動中も読めるように位相を始めからにする。
+// 桁の内容は通常どおり変更できる。ノンブロッキングなので、メインループから
+// Update(またはUpdateCursor)を呼び出す。
+func (d *Device) SetCursor(display int, position int) error {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits {
+		return ErrInvalidDisplay
+	}
+	d.cursorOn = true
+	d.cursorDisplay, d.cursorPosition = display, position
+	d.cursorStart = d.tick()
+	if d.cursorShown {
+		d.cursorShown = false
+		d.Display()
+	}
+	return nil
+}
+
+// HideCursor removes the cursor and shows the digit again.
+//
+// HideCursorは、カーソルを消して桁を再び表示する。
+func (d *Device) HideCursor() {
+	d.cursorOn = false
+	if d.cursorShown {
+		d.cursorShown = false
+		d.Display()
+	}
+}
+
+// SetCursorRate sets the period of the cursor; 0 means one second.
+//
+// SetCursorRateは、カーソルの周期を設定する。0なら1秒になる。
+func (d *Device) SetCursorRate(period time.Duration) {
+	d.cursorPeriod = delayTicks(period)
+}
+
+// UpdateCursor drives the cursor set by SetCursor, sending the display only
+// when the digit and the underscore change places. It returns true while
+// the cursor is shown.
+//
+// UpdateCursorは、SetCursorで設定したカーソルを動かし、桁とアンダースコアが
+// 入れ替わるときだけディスプレイに送る。カーソルを表示している間はtrueを
+// 返す。
+func (d *Device) UpdateCursor() bool {
+	if !d.cursorOn {
+		return false
+	}
+	period := tickInterval(d.cursorPeriod, defaultCursorPeriod)
+	shown := (d.tick()-d.cursorStart)%period >= period/2
+	if shown != d.cursorShown {
+		d.cursorShown = shown
+		d.Display()
+	}
+	return true
+}
+
+// applyCursor replaces the digit under the cursor in frame by the
+// underscore.
+//
+// applyCursorは、frameのカーソルの下の桁をアンダースコアに置き換える。
+func (d *Device) applyCursor(frame *[16]byte) {
+	rowOffset := d.cursorDisplay * segmentRows
+	for row := 0; row < segmentRows; row++ {
+		frame[rowOffset+row] &^= 1 << d.cursorPosition
+		if cursorPattern&(1<<row) != 0 {
+			frame[rowOffset+row] |= 1 << d.cursorPosition
+		}
+	}
+}
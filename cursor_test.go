@@ -0,0 +1,53 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSetCursor verifies that the digit under the cursor alternates with an underscore.
+func TestSetCursor(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "1234")
+	device.Display()
+	device.SetCursorRate(time.Hour)
+
+	if err := device.SetCursor(0, 2); err != nil {
+		t.Fatalf("SetCursor() returned an unexpected error: %v", err)
+	}
+	if !device.UpdateCursor() || mockBus.ram != device.buffer {
+		t.Fatalf("FAIL: Digit should be shown in the first half, got %x", mockBus.ram)
+	}
+
+	advanceClock(device, time.Hour/2)
+	device.Update()
+	content := device.buffer
+	device.SetBuffer(mockBus.ram)
+	if got := device.digitPattern(0, 2); got != cursorPattern {
+		t.Errorf("FAIL: Digit should be an underscore in the second half!\nExpected: %x\nGot:      %x", cursorPattern, got)
+	}
+	for row := range content {
+		if mask := byte(1 << 2); content[row]&^mask != device.buffer[row]&^mask {
+			t.Errorf("FAIL: Other digits should stay!\nExpected: %x\nGot:      %x", content, device.buffer)
+			break
+		}
+	}
+
+	// Moving the cursor shows the content again and restarts the phase.
+	device.WriteString(0, "1234")
+	device.SetCursor(0, 3)
+	if mockBus.ram != device.buffer {
+		t.Errorf("FAIL: SetCursor() should show the digit again, got %x", mockBus.ram)
+	}
+	advanceClock(device, time.Hour/2)
+	device.Update()
+	device.HideCursor()
+	if device.UpdateCursor() || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: HideCursor() should show the digit again, got %x", mockBus.ram)
+	}
+
+	if err := device.SetCursor(2, 0); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: SetCursor() with an invalid display should return ErrInvalidDisplay, got %v", err)
+	}
+}
@@ -0,0 +1,247 @@
+package ht16k33
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expandLayout replaces each run of the same letter in layout, such as "HH"
+// or "SS", with the text that field returns for it. Letters that field does
+// not know and all other characters are kept as they are.
+//
+// expandLayoutは、layout内の同じ英字の並び("HH"や"SS"など)を、fieldが返す
+// テキストに置き換える。fieldが知らない英字やその他の文字はそのまま残す。
+func expandLayout(layout string, field func(letter byte, n int) (string, bool)) string {
+	var b strings.Builder
+	for i := 0; i < len(layout); {
+		n := 1
+		for i+n < len(layout) && layout[i+n] == layout[i] {
+			n++
+		}
+		if text, ok := field(layout[i], n); ok {
+			b.WriteString(text)
+		} else {
+			b.WriteString(layout[i : i+n])
+		}
+		i += n
+	}
+	return b.String()
+}
+
+// zeroPad formats v with at least n digits.
+//
+// zeroPadは、vを少なくともn桁で整形する。
+func zeroPad(v int64, n int) string {
+	text := strconv.FormatInt(v, 10)
+	if len(text) < n {
+		text = strings.Repeat("0", n-len(text)) + text
+	}
+	return text
+}
+
+// writeLayout clears the display and writes an expanded layout
+// right-aligned, or leaves the display unchanged and returns ErrOverflow if
+// it does not fit.
+//
+// writeLayoutは、ディスプレイをクリアし、展開したレイアウトを右詰めで書き
+// 込む。収まらない場合はディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) writeLayout(display int, text string) error {
+	cells, _, err := d.layoutString(text)
+	if err != nil {
+		return err
+	}
+	if len(cells) > d.width(display) {
+		return ErrOverflow
+	}
+	d.writeRightAligned(display, cells)
+	return nil
+}
+
+// WriteDuration displays a duration right-aligned, using a layout made of
+// "HH" (hours), "MM" (minutes), "SS" (seconds) and "T" (tenths; "TT" for
+// hundredths), such as "MM.SS", "HH.MM.SS" or "MM.SS.T". A '.' lights the
+// DP of the digit before it. The largest unit in the layout is not wrapped,
+// so 75 minutes in "MM.SS" is shown as "75.00". Negative durations and
+// durations that do not fit return ErrOverflow and leave the display
+// unchanged.
+//
+// WriteDurationは、"HH"(時間)、"MM"(分)、"SS"(秒)、"T"(10分の1秒、"TT"で
+// 100分の1秒)からなるレイアウト("MM.SS"、"HH.MM.SS"、"MM.SS.T"など)で
+// 経過時間を右詰めで表示する。'.'は前の桁のDPを点灯する。レイアウト内の最も
+// 大きい単位は繰り上がらないので、"MM.SS"で75分は"75.00"と表示される。負の
+// 値や収まらない値はディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) WriteDuration(display int, dur time.Duration, layout string) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if dur < 0 {
+		return ErrOverflow
+	}
+	hasHours := strings.Contains(layout, "HH")
+	hasMinutes := strings.Contains(layout, "MM")
+	text := expandLayout(layout, func(letter byte, n int) (string, bool) {
+		switch letter {
+		case 'H':
+			return zeroPad(int64(dur/time.Hour), n), true
+		case 'M':
+			minutes := int64(dur / time.Minute)
+			if hasHours {
+				minutes %= 60
+			}
+			return zeroPad(minutes, n), true
+		case 'S':
+			seconds := int64(dur / time.Second)
+			if hasHours || hasMinutes {
+				seconds %= 60
+			}
+			return zeroPad(seconds, n), true
+		case 'T':
+			fraction := int64(dur % time.Second)
+			for i := n; i < 9; i++ {
+				fraction /= 10
+			}
+			return zeroPad(fraction, n), true
+		}
+		return "", false
+	})
+	return d.writeLayout(display, text)
+}
+
+// WriteTime displays the time of day right-aligned, using a layout made of
+// "HH" (hours, 00-23), "hh" (hours, 01-12), "MM" (minutes) and "SS"
+// (seconds), such as "HH.MM" or "HH.MM.SS". The '.' and ':' separators can
+// be blinked with StartSeparatorBlink. If the layout does not fit, the
+// display is left unchanged and ErrOverflow is returned.
+//
+// WriteTimeは、"HH"(時、00-23)、"hh"(時、01-12)、"MM"(分)、"SS"(秒)から
+// なるレイアウト("HH.MM"や"HH.MM.SS"など)で時刻を右詰めで表示する。'.'と
+// ':'の区切りはStartSeparatorBlinkで点滅できる。レイアウトが収まらない場合
+// はディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) WriteTime(display int, t time.Time, layout string) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	text := expandLayout(layout, func(letter byte, n int) (string, bool) {
+		switch letter {
+		case 'H':
+			return zeroPad(int64(t.Hour()), n), true
+		case 'h':
+			hour := t.Hour() % 12
+			if hour == 0 {
+				hour = 12
+			}
+			return zeroPad(int64(hour), n), true
+		case 'M':
+			return zeroPad(int64(t.Minute()), n), true
+		case 'S':
+			return zeroPad(int64(t.Second()), n), true
+		}
+		return "", false
+	})
+	cells, _, err := d.layoutString(text)
+	if err != nil {
+		return err
+	}
+	if len(cells) > d.digits {
+		return ErrOverflow
+	}
+	d.writeRightAligned(display, cells)
+	d.markSeparators(display, d.digits-len(cells), cells)
+	return nil
+}
+
+// markSeparators records the decimal points and the colon LED lit by cells
+// written from start, so that they can blink.
+//
+// markSeparatorsは、点滅できるように、startから書き込んだcellsが点灯させた
+// 小数点とコロンLEDを記録する。
+func (d *Device) markSeparators(display int, start int, cells []cell) {
+	rowOffset := display * segmentRows
+	for i, c := range cells {
+		if c.dot {
+			d.separators[rowOffset+int(SegmentDP)] |= 1 << (start + i)
+		}
+		if colon := d.colons[display]; c.colon && colon.ok {
+			d.separators[rowOffset+int(colon.seg)] |= 1 << colon.position
+		}
+	}
+}
+
+// StartSeparatorBlink starts blinking the separators written by WriteTime at
+// 1 Hz. It is non-blocking: call UpdateSeparatorBlink from the main loop,
+// like UpdateFade.
+//
+// StartSeparatorBlinkは、WriteTimeで書き込んだ区切りを1Hzで点滅させ始める。
+// ノンブロッキングなので、UpdateFadeと同様にメインループから
+// UpdateSeparatorBlinkを呼び出す。
+func (d *Device) StartSeparatorBlink() {
+	if d.separatorBlink {
+		return
+	}
+	d.separatorBlink = true
+	d.blinkStart = d.tick()
+}
+
+// StopSeparatorBlink stops blinking and shows the separators again.
+//
+// StopSeparatorBlinkは、点滅を止めて区切りを再び表示する。
+func (d *Device) StopSeparatorBlink() {
+	d.separatorBlink = false
+	if d.separatorsOff {
+		d.separatorsOff = false
+		d.Display()
+	}
+}
+
+// UpdateSeparatorBlink drives the separator blink started by
+// StartSeparatorBlink. The separators are shown for the first half of each
+// second and hidden for the second half; the display is only sent when this
+// changes. It returns true while blinking.
+//
+// UpdateSeparatorBlinkは、StartSeparatorBlinkで始めた区切りの点滅を動かす。
+// 区切りは毎秒の前半に表示し、後半に消す。変化したときだけディスプレイに
+// 送る。点滅中はtrueを返す。
+func (d *Device) UpdateSeparatorBlink() bool {
+	if !d.separatorBlink {
+		return false
+	}
+	off := d.sinceTick(d.blinkStart)%time.Second >= time.Second/2
+	if off != d.separatorsOff {
+		d.separatorsOff = off
+		d.Display()
+	}
+	return true
+}
+
+// WriteDate displays a date right-aligned, using a layout made of "YYYY" or
+// "YY" (year), "MM" (month) and "DD" (day), such as "DD.MM.YY" or
+// "YYYY.MM.DD". Together with WriteTime, one display can show the date and
+// the other the time. If the layout does not fit, the display is left
+// unchanged and ErrOverflow is returned.
+//
+// WriteDateは、"YYYY"または"YY"(年)、"MM"(月)、"DD"(日)からなるレイアウト
+// ("DD.MM.YY"や"YYYY.MM.DD"など)で日付を右詰めで表示する。WriteTimeと
+// 組み合わせると、一方のディスプレイに日付、もう一方に時刻を表示できる。
+// レイアウトが収まらない場合はディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) WriteDate(display int, t time.Time, layout string) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	text := expandLayout(layout, func(letter byte, n int) (string, bool) {
+		switch letter {
+		case 'Y':
+			year := int64(t.Year())
+			if n < 4 {
+				year %= 100
+			}
+			return zeroPad(year, n), true
+		case 'M':
+			return zeroPad(int64(t.Month()), n), true
+		case 'D':
+			return zeroPad(int64(t.Day()), n), true
+		}
+		return "", false
+	})
+	return d.writeLayout(display, text)
+}
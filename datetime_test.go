@@ -0,0 +1,111 @@
+package ht16k33
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestWriteDuration verifies the duration layouts.
+func TestWriteDuration(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	tests := []struct {
+		dur      time.Duration
+		layout   string
+		expected string
+	}{
+		{83 * time.Second, "MM.SS", "    01.23"},
+		{75 * time.Minute, "MM.SS", "    75.00"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "HH.MM.SS", "  01.02.03"},
+		{65*time.Second + 470*time.Millisecond, "MM.SS.T", "   01.05.4"},
+		{5*time.Second + 470*time.Millisecond, "SS.TT", "    05.47"},
+	}
+	for _, test := range tests {
+		if err := device.WriteDuration(0, test.dur, test.layout); err != nil {
+			t.Fatalf("WriteDuration(%v, %q) returned an unexpected error: %v", test.dur, test.layout, err)
+		}
+		assertDisplay(t, device, 0, test.expected)
+	}
+
+	if err := device.WriteDuration(0, -time.Second, "MM.SS"); err != ErrOverflow {
+		t.Errorf("FAIL: WriteDuration() with a negative duration should return ErrOverflow, got %v", err)
+	}
+	if err := device.WriteDuration(0, 1000000*time.Hour, "HH.MM.SS"); err != ErrOverflow {
+		t.Errorf("FAIL: WriteDuration() with a long duration should return ErrOverflow, got %v", err)
+	}
+	assertDisplay(t, device, 0, "    05.47")
+}
+
+// TestWriteTime verifies the time layouts.
+func TestWriteTime(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	now := time.Date(2024, 5, 6, 13, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"HH.MM", "    13.04"},
+		{"HH.MM.SS", "  13.04.05"},
+		{"hh.MM", "    01.04"},
+	}
+	for _, test := range tests {
+		if err := device.WriteTime(0, now, test.layout); err != nil {
+			t.Fatalf("WriteTime(%q) returned an unexpected error: %v", test.layout, err)
+		}
+		assertDisplay(t, device, 0, test.expected)
+	}
+}
+
+// TestSeparatorBlink verifies that only the separators of WriteTime are blinked.
+func TestSeparatorBlink(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(1, "1.2")
+	device.WriteTime(0, time.Date(2024, 5, 6, 13, 4, 5, 0, time.UTC), "HH.MM")
+
+	device.StartSeparatorBlink()
+	advanceClock(device, 600*time.Millisecond)
+	if !device.UpdateSeparatorBlink() {
+		t.Errorf("FAIL: UpdateSeparatorBlink() should return true while blinking")
+	}
+	expected := device.buffer
+	expected[int(SegmentDP)] &^= 1 << 5
+	if !bytes.Equal(mockBus.data[1:], expected[:]) {
+		t.Errorf("FAIL: Separator is not hidden!\nExpected: %x\nGot:      %x", expected[:], mockBus.data[1:])
+	}
+
+	device.StopSeparatorBlink()
+	if !bytes.Equal(mockBus.data[1:], device.buffer[:]) {
+		t.Errorf("FAIL: Separator is not shown again!\nExpected: %x\nGot:      %x", device.buffer[:], mockBus.data[1:])
+	}
+	if device.UpdateSeparatorBlink() {
+		t.Errorf("FAIL: UpdateSeparatorBlink() should return false after StopSeparatorBlink")
+	}
+}
+
+// TestWriteDate verifies the date layouts.
+func TestWriteDate(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	date := time.Date(2024, 5, 6, 13, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		layout   string
+		expected string
+	}{
+		{"DD.MM.YY", "  06.05.24"},
+		{"YYYY.MM.DD", "2024.05.06"},
+		{"MM-DD", "   05-06"},
+	}
+	for _, test := range tests {
+		if err := device.WriteDate(0, date, test.layout); err != nil {
+			t.Fatalf("WriteDate(%q) returned an unexpected error: %v", test.layout, err)
+		}
+		assertDisplay(t, device, 0, test.expected)
+	}
+
+	if err := device.WriteDate(0, date, "YYYY-MM-DD"); err != ErrOverflow {
+		t.Errorf("FAIL: WriteDate() with a long layout should return ErrOverflow, got %v", err)
+	}
+}
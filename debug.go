@@ -0,0 +1,192 @@
+package ht16k33
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Stats counts the I2C transactions made by a Device.
+//
+// Statsは、Deviceが行ったI2Cの通信を数える。
+type Stats struct {
+	// Transactions is the number of I2C transactions, including failed ones.
+	// Transactionsは、失敗したものを含むI2Cの通信の回数。
+	Transactions uint32
+	// Errors is the number of transactions that returned an error.
+	// Errorsは、エラーを返した通信の回数。
+	Errors uint32
+	// LastError is the most recent error, or nil.
+	// LastErrorは、最後に起きたエラー。なければnil。
+	LastError error
+}
+
+// Stats returns the I2C transaction counters.
+//
+// Statsは、I2Cの通信のカウンタを返す。
+func (d *Device) Stats() Stats {
+	return d.stats
+}
+
+// State is a snapshot of what a Device is showing, for reporting the
+// display state over a debug channel.
+//
+// Stateは、Deviceが表示している内容のスナップショットで、デバッグ用の通信
+// 経路で表示の状態を報告するために使う。
+type State struct {
+	Address      uint8
+	Buffer       [16]byte
+	Brightness   uint8
+	DisplayOn    bool
+	Fading       bool
+	FadeProgress uint8
+	Stats        Stats
+}
+
+// StateSnapshot returns the current state of the Device.
+//
+// StateSnapshotは、Deviceの現在の状態を返す。
+func (d *Device) StateSnapshot() State {
+	return State{
+		Address:      d.Address,
+		Buffer:       d.buffer,
+		Brightness:   d.currentBrightness,
+		DisplayOn:    d.displayOn,
+		Fading:       d.IsFading(),
+		FadeProgress: d.FadeProgress(),
+		Stats:        d.stats,
+	}
+}
+
+// MarshalJSON encodes the state of the Device as JSON (see State.MarshalJSON).
+//
+// MarshalJSONは、Deviceの状態をJSONにする(State.MarshalJSONを参照)。
+func (d *Device) MarshalJSON() ([]byte, error) {
+	return d.StateSnapshot().MarshalJSON()
+}
+
+// MarshalJSON encodes the state as JSON without using reflection, so it
+// stays small on TinyGo. The buffer is encoded as an array of 16 numbers
+// and the last error as a string or null, for example:
+//
+//	{"address":112,"buffer":[0,...],"brightness":15,"displayOn":true,
+//	 "fading":false,"fadeProgress":100,
+//	 "i2c":{"transactions":4,"errors":0,"lastError":null}}
+//
+// MarshalJSONは、リフレクションを使わずに状態をJSONにするので、TinyGoでも
+// 小さく収まる。バッファは16個の数値の配列に、最後のエラーは文字列か
+// nullになる。
+func (s State) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 256)
+	b = append(b, `{"address":`...)
+	b = strconv.AppendUint(b, uint64(s.Address), 10)
+	b = append(b, `,"buffer":[`...)
+	for i, row := range s.Buffer {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendUint(b, uint64(row), 10)
+	}
+	b = append(b, `],"brightness":`...)
+	b = strconv.AppendUint(b, uint64(s.Brightness), 10)
+	b = append(b, `,"displayOn":`...)
+	b = strconv.AppendBool(b, s.DisplayOn)
+	b = append(b, `,"fading":`...)
+	b = strconv.AppendBool(b, s.Fading)
+	b = append(b, `,"fadeProgress":`...)
+	b = strconv.AppendUint(b, uint64(s.FadeProgress), 10)
+	b = append(b, `,"i2c":{"transactions":`...)
+	b = strconv.AppendUint(b, uint64(s.Stats.Transactions), 10)
+	b = append(b, `,"errors":`...)
+	b = strconv.AppendUint(b, uint64(s.Stats.Errors), 10)
+	b = append(b, `,"lastError":`...)
+	if s.Stats.LastError != nil {
+		b = appendJSONString(b, s.Stats.LastError.Error())
+	} else {
+		b = append(b, "null"...)
+	}
+	b = append(b, "}}"...)
+	return b, nil
+}
+
+// appendJSONString appends s as a JSON string. Unlike strconv.AppendQuote,
+// it escapes the control characters as JSON does, with \u00XX, and replaces
+// invalid UTF-8 with U+FFFD.
+//
+// appendJSONStringは、sをJSONの文字列として追加する。strconv.AppendQuoteと
+// 違い、制御文字をJSONと同じく\u00XXでエスケープし、不正なUTF-8はU+FFFDに
+// 置き換える。
+func appendJSONString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			b = append(b, '\\', byte(r))
+		case r == '\n':
+			b = append(b, `\n`...)
+		case r == '\r':
+			b = append(b, `\r`...)
+		case r == '\t':
+			b = append(b, `\t`...)
+		case r < 0x20:
+			b = append(b, `\u00`...)
+			b = append(b, hex[r>>4], hex[r&0xF])
+		default:
+			// A range over a string yields U+FFFD for invalid UTF-8.
+			b = utf8.AppendRune(b, r)
+		}
+	}
+	return append(b, '"')
+}
+
+// RenderASCII draws the buffer as ASCII-art 7-segment digits, three lines
+// per display with a blank line between the displays. It is meant for
+// printing the display state on a serial console or in test failures.
+//
+// RenderASCIIは、バッファをASCIIアートの7セグメントの桁として描く。
+// 1つのディスプレイにつき3行で、ディスプレイの間には空行が入る。
+// シリアルコンソールやテストの失敗時に表示の状態を出力するためのもの。
+//
+// For example, "8.15" on a display is drawn as:
+//
+//	 _       _
+//	|_|   | |_
+//	|_|.  |  _|
+func (d *Device) RenderASCII() string {
+	var sb strings.Builder
+	for display := 0; display < d.displays; display++ {
+		if display > 0 {
+			sb.WriteByte('\n')
+		}
+		rowOffset := display * segmentRows
+		lit := func(position int, seg Segment, on byte) byte {
+			if d.buffer[rowOffset+int(seg)]&(1<<position) != 0 {
+				return on
+			}
+			return ' '
+		}
+		for line := 0; line < 3; line++ {
+			for pos := 0; pos < d.digits; pos++ {
+				switch line {
+				case 0:
+					sb.WriteByte(' ')
+					sb.WriteByte(lit(pos, SegmentA, '_'))
+					sb.WriteString("  ")
+				case 1:
+					sb.WriteByte(lit(pos, SegmentF, '|'))
+					sb.WriteByte(lit(pos, SegmentG, '_'))
+					sb.WriteByte(lit(pos, SegmentB, '|'))
+					sb.WriteByte(' ')
+				case 2:
+					sb.WriteByte(lit(pos, SegmentE, '|'))
+					sb.WriteByte(lit(pos, SegmentD, '_'))
+					sb.WriteByte(lit(pos, SegmentC, '|'))
+					sb.WriteByte(lit(pos, SegmentDP, '.'))
+				}
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
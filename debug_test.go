@@ -0,0 +1,98 @@
+package ht16k33
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// TestRenderASCII verifies the ASCII-art rendering of both displays.
+func TestRenderASCII(t *testing.T) {
+	device, err := New(&mockI2C{}, 0x70, WithGeometry(2, 3))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	device.WriteString(0, "8.1")
+	device.WriteString(1, "-25")
+
+	expected := "" +
+		" _          \n" +
+		"|_|   |     \n" +
+		"|_|.  |     \n" +
+		"\n" +
+		"     _   _  \n" +
+		" _   _| |_  \n" +
+		"    |_   _| \n"
+	if got := device.RenderASCII(); got != expected {
+		t.Errorf("FAIL: RenderASCII() is wrong!\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+// TestStateSnapshotJSON verifies that the JSON state can be decoded again.
+func TestStateSnapshotJSON(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	if err := device.Configure(Config{Brightness: 9}); err != nil {
+		t.Fatalf("Configure() returned an unexpected error: %v", err)
+	}
+	device.WriteString(0, "1")
+	mockBus.err = errors.New("nack")
+	device.Display()
+
+	data, err := json.Marshal(device)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an unexpected error: %v", err)
+	}
+
+	var decoded struct {
+		Address      uint8
+		Buffer       [16]byte
+		Brightness   uint8
+		DisplayOn    bool
+		Fading       bool
+		FadeProgress uint8
+		I2C          struct {
+			Transactions uint32
+			Errors       uint32
+			LastError    *string
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("FAIL: Output is not valid JSON: %v\n%s", err, data)
+	}
+	if decoded.Address != 0x70 || decoded.Buffer != device.buffer || decoded.Brightness != 9 ||
+		!decoded.DisplayOn || decoded.Fading || decoded.FadeProgress != 100 {
+		t.Errorf("FAIL: Decoded state is wrong: %s", data)
+	}
+	if decoded.I2C.Transactions != 4 || decoded.I2C.Errors != 1 ||
+		decoded.I2C.LastError == nil || *decoded.I2C.LastError != "nack" {
+		t.Errorf("FAIL: Decoded I2C stats are wrong: %s", data)
+	}
+}
+
+// TestStateSnapshotJSONEscape verifies that the last error is escaped as a valid JSON string.
+func TestStateSnapshotJSONEscape(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	expected := "bus \x01 \"busy\"\t\\ \x7f\n\xff"
+	mockBus.err = errors.New(expected)
+	device.Display()
+
+	data, err := json.Marshal(device)
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an unexpected error: %v", err)
+	}
+	var decoded struct {
+		I2C struct {
+			LastError string
+		}
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("FAIL: Output is not valid JSON: %v\n%s", err, data)
+	}
+	// The invalid UTF-8 byte is replaced, as encoding/json does.
+	expected = expected[:len(expected)-1] + "�"
+	if decoded.I2C.LastError != expected {
+		t.Errorf("FAIL: Decoded last error is wrong!\nExpected: %q\nGot:      %q", expected, decoded.I2C.LastError)
+	}
+}
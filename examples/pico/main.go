@@ -27,8 +27,15 @@ func main() {
 
 	// --- ドライバの初期化 ---
 	// HT16K33のI2Cアドレス(通常は0x70)を指定
-	display := ht16k33.New(i2c, 0x70)
-	display.Configure()
+	display, err := ht16k33.New(i2c, 0x70)
+	if err != nil {
+		println("could not create display:", err.Error())
+		return
+	}
+	if err := display.Configure(ht16k33.Config{ClearRAM: true}); err != nil {
+		println("could not configure display:", err.Error())
+		return
+	}
 
 	// フェードの速さを決める
 	fadeDelay := 20 * time.Millisecond
@@ -37,57 +44,56 @@ func main() {
 	println("HT16K33 Demo Start!")
 
 	// --- ノンブロッキング版デモ ---
-	// 状態を管理するための変数
-	demoState := 0
-	lastActionTime := time.Now()
+	// デモの各ステップをSequenceとして並べる。Effectは始めた効果が終わるまで
+	// 待ち、Holdは指定した時間だけ待つ。
+	demo := ht16k33.NewSequence(
+		ht16k33.Effect(func(d *ht16k33.Device) {
+			println("1. Clearing all with fade...")
+			d.ClearAll()
+			d.StartFade(fadeDelay)
+		}),
+		ht16k33.Hold(2*time.Second),
+		ht16k33.Action(func(d *ht16k33.Device) {
+			println("2. Writing 'HELLO' to display 0...")
+			d.WriteString(0, "HELLO") // フェードなしで表示
+		}),
+		ht16k33.Hold(2*time.Second),
+		ht16k33.Effect(func(d *ht16k33.Device) {
+			println("3. Writing 'WORLD' to display 1 with fade...")
+			d.WriteString(1, "WORLD")
+			d.StartFade(fadeDelay)
+		}),
+		ht16k33.Hold(2*time.Second),
+		ht16k33.Effect(func(d *ht16k33.Device) {
+			println("4. Writing a long number with fade...")
+			// WriteString16は2つのディスプレイを1つの16桁ディスプレイ
+			// として扱い、ドットも自動で処理してくれる。
+			d.WriteString16("12345678.9012345.")
+			d.StartFade(fadeDelay)
+		}),
+		ht16k33.Hold(2*time.Second),
+		ht16k33.Effect(func(d *ht16k33.Device) {
+			println("5. Clearing display 0 with fade...")
+			d.ClearOnDisplay(0)
+			d.StartFade(fadeDelay)
+		}),
+		ht16k33.Hold(2*time.Second),
+		ht16k33.Effect(func(d *ht16k33.Device) {
+			println("6. Lighting up all segments (illumination mode)...")
+			d.LightUpAll()
+			d.StartFade(fadeDelay)
+		}),
+		ht16k33.Hold(2*time.Second),
+		ht16k33.Action(func(d *ht16k33.Device) {
+			println("7. Demo Finished! Restarting...")
+		}),
+	)
+	demo.SetLoop(true)
+	display.Animate(demo)
 
 	for {
-		// UpdateFadeを毎回呼び出して、フェードアニメーションを動かす
-		display.UpdateFade()
-
-		// フェード中でなければ、次のデモステップに進む
-		if !display.IsFading() && time.Since(lastActionTime) > 2*time.Second {
-			switch demoState {
-			case 0:
-				println("1. Clearing all with fade...")
-				display.ClearAll()
-				display.StartFade(fadeDelay)
-			case 1:
-				println("2. Writing 'HELLO' to display 0...")
-				display.WriteString(0, "HELLO")
-				display.Display() // フェードなしで表示
-			case 2:
-				println("3. Writing 'WORLD' to display 1 with fade...")
-				display.WriteString(1, "WORLD")
-				display.StartFade(fadeDelay)
-			case 3:
-				println("4. Writing a long number with fade...")
-				longNumber := "12345678.9012345."
-				for i, char := range longNumber {
-					// WriteStringはディスプレイをクリアしてしまうので、16
-					// 桁全体に書き込むにはSetDigit16をループで使う。
-					// ドットはWriteStringが自動で処理してくれるが、
-					// SetDigit16の場合は自分で処理する必要があるため。
-					// ここでは簡単にするためにドットは無視。`char` をその
-					// まま渡すのがポイント。
-					display.SetDigit16(i, char, false)
-				}
-				display.StartFade(fadeDelay)
-			case 4:
-				println("5. Clearing display 0 with fade...")
-				display.ClearOnDisplay(0)
-				display.StartFade(fadeDelay)
-			case 5:
-				println("6. Lighting up all segments (illumination mode)...")
-				// これはブロッキング関数なので、完了するまでここで待機する
-				display.LightUpAllFadeBlocking(fadeDelay)
-			case 6:
-				println("7. Demo Finished! Restarting...")
-				demoState = -1 // 次のループで0になる
-			}
-			demoState++
-			lastActionTime = time.Now()
-		}
+		// Updateを毎回呼び出して、デモやフェードなどのアニメーションを動かす
+		display.Update()
 
 		// ここで他の処理ができる
 		// 例えば、ボタン入力をチェックしたり、センサーの値を読んだり…
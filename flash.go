@@ -0,0 +1,98 @@
+package ht16k33
+
+import "time"
+
+// FlashStyle decides what StartFlash shows in the off half of each flash.
+//
+// FlashStyleは、StartFlashが各点滅の消灯側で何を表示するかを決める。
+type FlashStyle uint8
+
+const (
+	// FlashBlank blanks the displays (default).
+	FlashBlank FlashStyle = iota
+	// FlashInvert lights the segments that are off and turns off the ones
+	// that are lit.
+	FlashInvert
+)
+
+// SetFlashStyle sets what StartFlash shows in the off half of each flash.
+//
+// SetFlashStyleは、StartFlashが各点滅の消灯側で何を表示するかを設定する。
+func (d *Device) SetFlashStyle(style FlashStyle) {
+	d.flashStyle = style
+}
+
+// flashAnimation is the Animation started by StartFlash.
+//
+// flashAnimationは、StartFlashで始めるAnimation。
+type flashAnimation struct {
+	toggles  int
+	interval time.Duration
+	last     uint32
+	stopped  bool
+}
+
+// Start shows the off half of the first flash.
+func (f *flashAnimation) Start(d *Device, now time.Time) {
+	f.last = tickOf(now)
+	d.flashOff = true
+	d.Display()
+}
+
+// Step toggles between the content and the off half once per interval.
+func (f *flashAnimation) Step(d *Device, now time.Time) bool {
+	if f.stopped || tickDuration(tickOf(now)-f.last) < f.interval {
+		return false
+	}
+	f.last = tickOf(now)
+	f.toggles--
+	if f.toggles <= 0 {
+		f.stopped = true
+	}
+	d.flashOff = !d.flashOff && !f.stopped
+	return true
+}
+
+// Done reports whether the flash has finished.
+func (f *flashAnimation) Done() bool {
+	return f.stopped
+}
+
+// Stop shows the content at once.
+func (f *flashAnimation) Stop(d *Device) {
+	f.stopped = true
+	d.flashOff = false
+}
+
+// StartFlash flashes the displays times times to draw attention, for alarms
+// and errors: each flash shows the off half (blank, or inverted with
+// FlashInvert) for interval, then the content for interval. The buffer is
+// not changed, so the content is shown as it is after the flash. It is
+// non-blocking: call Update from the main loop. Starting a flash replaces
+// the running one.
+//
+// StartFlashは、アラームやエラーで注意を引くためにディスプレイをtimes回
+// 点滅させる。各点滅では、消灯側(空白、またはFlashInvertなら反転)を
+// intervalの間、次に内容をintervalの間表示する。バッファは変更しないので、
+// 点滅の後は内容がそのまま表示される。ノンブロッキングなので、メインループ
+// からUpdateを呼び出す。点滅を始めると、動いているものを置き換える。
+func (d *Device) StartFlash(times int, interval time.Duration) {
+	if d.flash != nil {
+		d.flash.stopped = true
+	}
+	d.flash = nil
+	d.flashOff = false
+	if times <= 0 {
+		d.Display()
+		return
+	}
+	d.flash = &flashAnimation{toggles: 2*times - 1, interval: interval}
+	d.Animate(d.flash)
+}
+
+// IsFlashing returns true while a flash started by StartFlash is running.
+//
+// IsFlashingは、StartFlashで始めた点滅が動いている間はtrueを返す。
+func (d *Device) IsFlashing() bool {
+	return d.flash != nil && !d.flash.Done()
+}
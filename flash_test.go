@@ -0,0 +1,54 @@
+package ht16k33
+
+import "testing"
+
+// TestStartFlash verifies that the displays blank and come back the given number of times.
+func TestStartFlash(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "1")
+	device.Display()
+	content := mockBus.ram
+
+	device.StartFlash(2, 0)
+	expected := []bool{true, false, true, false}
+	for i, off := range expected {
+		if got := mockBus.ram == [16]byte{}; got != off {
+			t.Errorf("FAIL: Step %d should be blank: %v, got %x", i, off, mockBus.ram)
+		}
+		if i < len(expected)-1 && !device.IsFlashing() {
+			t.Fatalf("FAIL: Flash finished early at step %d", i)
+		}
+		device.Update()
+	}
+	if device.IsFlashing() || mockBus.ram != content {
+		t.Errorf("FAIL: Flash should finish with the content shown, got %x", mockBus.ram)
+	}
+	if device.GetBuffer() != content {
+		t.Errorf("FAIL: Flash should not change the buffer")
+	}
+}
+
+// TestSetFlashStyle verifies that FlashInvert inverts the enabled displays in the off half.
+func TestSetFlashStyle(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "1")
+	device.EnableDisplay(1, false)
+	device.SetFlashStyle(FlashInvert)
+
+	device.StartFlash(1, 0)
+	for row := 0; row < segmentRows; row++ {
+		if want := ^device.buffer[row]; mockBus.ram[row] != want {
+			t.Errorf("FAIL: Row %d should be inverted!\nExpected: %x\nGot:      %x", row, want, mockBus.ram[row])
+		}
+		if mockBus.ram[segmentRows+row] != 0 {
+			t.Errorf("FAIL: Disabled display should stay blank, got %x", mockBus.ram[segmentRows+row])
+		}
+	}
+
+	device.StartFlash(0, 0)
+	if device.IsFlashing() || mockBus.ram != device.frame() {
+		t.Errorf("FAIL: StartFlash(0) should stop the flash, got %x", mockBus.ram)
+	}
+}
@@ -0,0 +1,152 @@
+package ht16k33
+
+import "time"
+
+// flipLevels are the segments of a digit from top to bottom. A flip turns
+// the old segments off level by level from the top, then the new ones on
+// level by level from the bottom.
+//
+// flipLevelsは、桁のセグメントを上から下へ並べたもの。フリップでは古い
+// セグメントを上の段から消し、新しいセグメントを下の段から点ける。
+var flipLevels = [...]byte{
+	segA,
+	segB | segF,
+	segG,
+	segC | segE,
+	segD | 0x80, // Bit 7 is the decimal point
+}
+
+// flipFrames is the number of frames of a flip.
+const flipFrames = 2 * len(flipLevels)
+
+// flipState is a running split-flap animation on one display.
+//
+// flipStateは、1つのディスプレイで動いているスプリットフラップ風の
+// アニメーション。
+type flipState struct {
+	active   bool
+	from, to [MaxDigitsPerDisplay]byte
+	changed  uint8
+	cells    []cell
+	frame    int
+	delay    time.Duration
+	last     uint32
+}
+
+// FlipTo changes a display to s like a split-flap display: the digits whose
+// content changes turn their segments off from top to bottom and the new
+// ones on from bottom to top, one level per frameDelay. The other digits
+// change at once. It is non-blocking: call UpdateFlip from the main loop,
+// like UpdateFade. s is laid out left-aligned, as by WriteString.
+//
+// FlipToは、ディスプレイをスプリットフラップ表示のようにsに変える。内容が
+// 変わる桁は、セグメントを上から下へ消し、新しいセグメントを下から上へ
+// frameDelayごとに1段ずつ点ける。その他の桁はすぐに変わる。ノンブロッキング
+// なので、UpdateFadeと同様にメインループからUpdateFlipを呼び出す。sは
+// WriteStringと同様に左寄せで配置する。
+func (d *Device) FlipTo(display int, s string, frameDelay time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	cells, _, err := d.layoutString(s)
+	if err != nil {
+		return err
+	}
+	if len(cells) > d.digits {
+		cells = cells[:d.digits]
+	}
+	flip := &d.flips[display]
+	*flip = flipState{cells: cells, delay: frameDelay, last: d.tick()}
+	for pos := 0; pos < d.digits; pos++ {
+		flip.from[pos] = d.digitPattern(display, pos)
+		if pos < len(cells) {
+			flip.to[pos] = cells[pos].pattern
+			if cells[pos].dot {
+				flip.to[pos] |= 0x80
+			}
+		}
+		if flip.from[pos] != flip.to[pos] {
+			flip.changed |= 1 << pos
+		}
+	}
+	flip.active = flip.changed != 0
+	d.setColon(display, false)
+	d.drawFlip(display, flip)
+	d.Display()
+	return nil
+}
+
+// UpdateFlip drives the flips started by FlipTo. It returns true while any
+// display is flipping.
+//
+// UpdateFlipは、FlipToで始めたフリップを動かす。いずれかのディスプレイが
+// フリップ中であればtrueを返す。
+func (d *Device) UpdateFlip() bool {
+	if d.paused {
+		return d.IsFlipping()
+	}
+	changed := false
+	for display := range d.flips {
+		flip := &d.flips[display]
+		if !flip.active || d.sinceTick(flip.last) < flip.delay {
+			continue
+		}
+		flip.last = d.tick()
+		flip.frame++
+		if flip.frame >= flipFrames-1 {
+			flip.active = false
+		}
+		d.drawFlip(display, flip)
+		changed = true
+	}
+	if changed {
+		d.Display()
+	}
+	return d.IsFlipping()
+}
+
+// IsFlipping returns true if any display is flipping.
+//
+// IsFlippingは、いずれかのディスプレイがフリップ中であればtrueを返す。
+func (d *Device) IsFlipping() bool {
+	for _, flip := range d.flips {
+		if flip.active {
+			return true
+		}
+	}
+	return false
+}
+
+// drawFlip draws the current frame of a flip into the buffer. The last
+// frame is the new content, including its colon.
+//
+// drawFlipは、フリップの現在のフレームをバッファに描く。最後のフレームは
+// コロンも含めた新しい内容になる。
+func (d *Device) drawFlip(display int, flip *flipState) {
+	if !flip.active {
+		d.clearDisplay(display)
+		d.writeCells(display, 0, flip.cells)
+		return
+	}
+	var mask byte
+	pattern := flip.from
+	if flip.frame < len(flipLevels) {
+		// Old segments go off from the top.
+		for _, level := range flipLevels[flip.frame+1:] {
+			mask |= level
+		}
+	} else {
+		// New segments come on from the bottom.
+		pattern = flip.to
+		for _, level := range flipLevels[flipFrames-flip.frame-1:] {
+			mask |= level
+		}
+	}
+	for pos := 0; pos < d.digits; pos++ {
+		p := flip.to[pos]
+		if flip.changed&(1<<pos) != 0 {
+			p = pattern[pos] & mask
+		}
+		d.setPattern(display, pos, p&0x7F, p&0x80 != 0)
+	}
+}
@@ -0,0 +1,62 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// stepFlip makes the next UpdateFlip advance every flip by one frame.
+func stepFlip(device *Device) bool {
+	advanceClock(device, time.Hour)
+	return device.UpdateFlip()
+}
+
+// TestFlipTo verifies the frames of a changing digit and that other digits change at once.
+func TestFlipTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "18")
+
+	if err := device.FlipTo(0, "17", time.Millisecond); err != nil {
+		t.Fatalf("FlipTo() returned an unexpected error: %v", err)
+	}
+	if got := device.digitPattern(0, 0); got != segB|segC {
+		t.Errorf("FAIL: Unchanged digit should not flip!\nExpected: %x\nGot:      %x", segB|segC, got)
+	}
+
+	// '8' loses its segments from the top, then '7' comes on from the bottom.
+	expected := []byte{
+		segB | segC | segD | segE | segF | segG,
+		segC | segD | segE | segG,
+		segC | segD | segE,
+		segD,
+		0,
+		0,
+		segC,
+		segC,
+		segB | segC,
+		segA | segB | segC,
+	}
+	for i, pattern := range expected {
+		if got := device.digitPattern(0, 1); got != pattern {
+			t.Errorf("FAIL: Frame %d is wrong!\nExpected: %x\nGot:      %x", i, pattern, got)
+		}
+		stepFlip(device)
+	}
+	if device.IsFlipping() {
+		t.Errorf("FAIL: IsFlipping() should return false after the last frame")
+	}
+	assertDisplay(t, device, 0, "17")
+}
+
+// TestFlipToUnchanged verifies that nothing flips if the content is the same.
+func TestFlipToUnchanged(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(1, "42")
+	device.FlipTo(1, "42", time.Millisecond)
+	if device.IsFlipping() {
+		t.Errorf("FAIL: IsFlipping() should return false for unchanged content")
+	}
+	if err := device.FlipTo(2, "42", time.Millisecond); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: FlipTo() on display 2 should return ErrInvalidDisplay, got %v", err)
+	}
+}
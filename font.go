@@ -0,0 +1,92 @@
+package ht16k33
+
+// --- 7-Segment Font Definition ---
+
+// The segments are mapped to bits in a byte, following the common g-f-e-d-c-b-a order.
+// セグメントは一般的な g-f-e-d-c-b-a の順でバイト内のビットにマッピングされている。
+//
+//	 a
+//	---
+//
+// f|g|b
+//
+//	---
+//
+// e| |c
+//
+//	---
+//	 d
+const (
+	segA byte = 1 << 0
+	segB byte = 1 << 1
+	segC byte = 1 << 2
+	segD byte = 1 << 3
+	segE byte = 1 << 4
+	segF byte = 1 << 5
+	segG byte = 1 << 6
+)
+
+// Font looks up the 7-segment pattern of a rune (bits 0-6 are segments a-g).
+// Glyph returns false if the rune has no pattern in the font.
+//
+// Fontは、ルーン文字の7セグメントのパターン(ビット0-6がセグメントa-g)を
+// 探す。フォントにパターンがなければGlyphはfalseを返す。
+type Font interface {
+	Glyph(r rune) (byte, bool)
+}
+
+// MapFont is a Font backed by a map from rune to pattern.
+//
+// MapFontは、ルーン文字からパターンへのマップによるFont。
+type MapFont map[rune]byte
+
+// Glyph returns the pattern of r.
+//
+// Glyphは、rのパターンを返す。
+func (f MapFont) Glyph(r rune) (byte, bool) {
+	pattern, ok := f[r]
+	return pattern, ok
+}
+
+// DefaultFont is the font used unless another one is given. It covers digits,
+// the letters A-Z, some punctuation and a few unit symbols. When built with
+// the ht16k33_minfont tag, it only covers digits, '-' and the space, to save
+// flash on small targets (the decimal point is always available).
+//
+// DefaultFontは、他のフォントを指定しない場合に使うフォント。数字、A-Zの
+// 英字、いくつかの記号と単位記号に対応する。ht16k33_minfontタグを付けて
+// ビルドすると、小さなターゲットのフラッシュを節約するため、数字、'-'、
+// 空白だけに対応する(小数点は常に使える)。
+var DefaultFont Font = asciiFont{}
+
+// asciiFont looks runes up in the asciiGlyphs table, without the hashing
+// and memory cost of a map on small microcontrollers.
+//
+// asciiFontは、asciiGlyphsの表からルーン文字を探す。小さなマイコンでの
+// マップのハッシュ計算やメモリの負担がない。
+type asciiFont struct{}
+
+// Glyph returns the pattern of r.
+//
+// Glyphは、rのパターンを返す。
+func (asciiFont) Glyph(r rune) (byte, bool) {
+	if r >= 0 && r < rune(len(asciiGlyphs)) {
+		// Only the space is blank, so a zero entry means "not in the font".
+		pattern := asciiGlyphs[r]
+		return pattern, pattern != 0 || r == ' '
+	}
+	return symbolGlyph(r)
+}
+
+// SetFont replaces the font used to render characters.
+// A nil font falls back to DefaultFont. Content already in the buffer is
+// not redrawn.
+//
+// SetFontは、文字の描画に使うフォントを差し替える。
+// nilを渡すとDefaultFontが使われる。既にバッファにある内容は描き直さない。
+func (d *Device) SetFont(font Font) {
+	if font == nil {
+		font = DefaultFont
+	}
+	d.font = font
+}
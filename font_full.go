@@ -0,0 +1,69 @@
+//go:build !ht16k33_minfont
+
+package ht16k33
+
+// asciiGlyphs maps an ASCII rune to its 7-segment pattern. This visual
+// representation makes it much easier to add or modify characters.
+// asciiGlyphsは、ASCIIのルーン文字を7セグメントのパターンにマッピングする。
+// 視覚的にどのセグメントが光るのかをわかりやすく表現している。
+var asciiGlyphs = [128]byte{
+	'0':  segA | segB | segC | segD | segE | segF,
+	'1':  segB | segC,
+	'2':  segA | segB | segG | segE | segD,
+	'3':  segA | segB | segG | segC | segD,
+	'4':  segF | segG | segB | segC,
+	'5':  segA | segF | segG | segC | segD,
+	'6':  segA | segF | segE | segD | segC | segG,
+	'7':  segA | segB | segC,
+	'8':  segA | segB | segC | segD | segE | segF | segG,
+	'9':  segA | segB | segC | segD | segF | segG,
+	'A':  segA | segB | segC | segE | segF | segG,
+	'B':  segF | segE | segD | segC | segG, // Lowercase 'b'
+	'C':  segA | segF | segE | segD,
+	'D':  segB | segC | segD | segE | segG, // Lowercase 'd'
+	'E':  segA | segF | segG | segE | segD,
+	'F':  segA | segF | segG | segE,
+	'G':  segA | segF | segE | segD | segC,
+	'H':  segF | segE | segG | segB | segC,
+	'I':  segB | segC, // Same as 1
+	'J':  segB | segC | segD | segE,
+	'K':  segA | segF | segE | segG | segC, // Approximation
+	'L':  segF | segE | segD,
+	'M':  segA | segB | segC | segE | segF,        // Approximation, like an upside-down 'U'
+	'N':  segE | segG | segC,                      // Lowercase 'n'
+	'O':  segA | segB | segC | segD | segE | segF, // Same as 0
+	'P':  segA | segB | segG | segF | segE,
+	'Q':  segA | segB | segC | segF | segG,
+	'R':  segE | segG,                      // Lowercase 'r'
+	'S':  segA | segF | segG | segC | segD, // Same as 5
+	'T':  segF | segE | segD | segG,        // Lowercase 't'
+	'U':  segB | segC | segD | segE | segF,
+	'V':  segE | segD | segC,               // Lowercase 'u'
+	'W':  segF | segB | segD,               // Approximation
+	'X':  segF | segE | segG | segB | segC, // Same as H
+	'Y':  segF | segG | segB | segC | segD,
+	'Z':  segA | segB | segG | segE | segD, // Same as 2
+	' ':  0,                                // Space
+	'-':  segG,
+	'_':  segD,
+	'\'': segB,
+	'"':  segB | segF,
+	'=':  segD | segG,
+	'?':  segA | segB | segG | segE,
+}
+
+// symbolGlyph returns the pattern of the non-ASCII symbols used in sensor
+// readouts.
+//
+// symbolGlyphは、センサーの表示に使うASCII以外の記号のパターンを返す。
+func symbolGlyph(r rune) (byte, bool) {
+	switch r {
+	case '°': // Degree
+		return segA | segB | segF | segG, true
+	case 'µ', 'μ': // Micro sign and Greek mu, a raised 'u' with a tail
+		return segF | segB | segG | segE, true
+	case '\u2126', 'Ω': // Ohm sign and Greek omega, same as M
+		return segA | segB | segC | segE | segF, true
+	}
+	return 0, false
+}
@@ -0,0 +1,57 @@
+//go:build !ht16k33_minfont
+
+package ht16k33
+
+import "testing"
+
+// TestFontAlphabet verifies that every letter A-Z is in the default font.
+func TestFontAlphabet(t *testing.T) {
+	for r := 'A'; r <= 'Z'; r++ {
+		if pattern, ok := DefaultFont.Glyph(r); !ok || pattern == 0 {
+			t.Errorf("FAIL: Letter %q has no glyph in the default font", r)
+		}
+	}
+}
+
+// TestDefaultFontLookup verifies the blank and missing entries of the ASCII table.
+func TestDefaultFontLookup(t *testing.T) {
+	testCases := []struct {
+		r          rune
+		expected   byte
+		expectedOK bool
+	}{
+		{r: ' ', expected: 0, expectedOK: true},
+		{r: '8', expected: segA | segB | segC | segD | segE | segF | segG, expectedOK: true},
+		{r: '~', expectedOK: false},
+		{r: 0, expectedOK: false},
+		{r: -1, expectedOK: false},
+		{r: '°', expected: segA | segB | segF | segG, expectedOK: true},
+		{r: 'あ', expectedOK: false},
+	}
+
+	for _, tc := range testCases {
+		pattern, ok := DefaultFont.Glyph(tc.r)
+		if pattern != tc.expected || ok != tc.expectedOK {
+			t.Errorf("FAIL: Glyph(%q) = %07b, %v; expected %07b, %v", tc.r, pattern, ok, tc.expected, tc.expectedOK)
+		}
+	}
+}
+
+// TestFontSymbols verifies sensor readouts with unit symbols.
+func TestFontSymbols(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	if n, err := device.WriteString(0, "23.5°C"); n != 6 || err != nil {
+		t.Fatalf("FAIL: WriteString() = %d, %v; expected 6, nil", n, err)
+	}
+	// The degree sign lights segments a, b, f and g of digit 3.
+	for _, row := range []int{0, 1, 5, 6} {
+		if device.buffer[row]&(1<<3) == 0 {
+			t.Errorf("FAIL: Segment row %d of the degree sign is not lit", row)
+		}
+	}
+	for _, r := range "µμ\u2126Ω" {
+		if _, ok := DefaultFont.Glyph(r); !ok {
+			t.Errorf("FAIL: Symbol %q is not in the default font", r)
+		}
+	}
+}
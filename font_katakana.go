@@ -0,0 +1,84 @@
+package ht16k33
+
+// KatakanaFont renders Japanese Katakana as best-effort 7-segment
+// approximations, for status messages on Japanese displays. Both full-width
+// (ア) and half-width (ｱ) forms are accepted. Many kana cannot be told apart
+// on seven segments, so several of them share a pattern. Runes that are not
+// Katakana are looked up in DefaultFont, so digits and Latin letters keep
+// working.
+//
+// KatakanaFontは、日本語のカタカナを7セグメントでできるだけ近い形で描画する
+// フォントで、日本語の状態表示に使う。全角(ア)と半角(ｱ)の両方を受け付ける。
+// 7セグメントでは区別できない文字が多いので、いくつかは同じパターンになる。
+// カタカナ以外の文字はDefaultFontから探すので、数字や英字もそのまま使える。
+var KatakanaFont Font = katakanaFont{}
+
+type katakanaFont struct{}
+
+// katakanaGlyphs lists each kana in its full-width and half-width forms.
+//
+// katakanaGlyphsは、各カナを全角と半角の形で列挙する。
+var katakanaGlyphs = [...]struct {
+	full, half rune
+	pattern    byte
+}{
+	{'ア', 'ｱ', segA | segB | segC | segG},
+	{'イ', 'ｲ', segG | segB | segC},
+	{'ウ', 'ｳ', segF | segA | segB | segC | segD},
+	{'エ', 'ｴ', segA | segG | segD},
+	{'オ', 'ｵ', segB | segC | segG | segE},
+	{'カ', 'ｶ', segA | segB | segC | segE | segF},
+	{'キ', 'ｷ', segA | segG | segB | segC},
+	{'ク', 'ｸ', segF | segA | segB | segC},
+	{'ケ', 'ｹ', segF | segE | segG | segB},
+	{'コ', 'ｺ', segA | segB | segC | segD},
+	{'サ', 'ｻ', segF | segB | segG | segC},
+	{'シ', 'ｼ', segF | segB | segD},
+	{'ス', 'ｽ', segA | segB | segG | segE},
+	{'セ', 'ｾ', segF | segG | segB | segE | segD},
+	{'ソ', 'ｿ', segF | segB | segC},
+	{'タ', 'ﾀ', segF | segA | segB | segC | segG},
+	{'チ', 'ﾁ', segA | segG | segC},
+	{'ツ', 'ﾂ', segF | segB | segC | segD},
+	{'テ', 'ﾃ', segA | segG | segC},
+	{'ト', 'ﾄ', segF | segE | segG},
+	{'ナ', 'ﾅ', segG | segB | segC | segA},
+	{'ニ', 'ﾆ', segA | segD},
+	{'ヌ', 'ﾇ', segA | segB | segC | segG | segE},
+	{'ネ', 'ﾈ', segA | segG | segE | segC},
+	{'ノ', 'ﾉ', segB | segE},
+	{'ハ', 'ﾊ', segE | segC},
+	{'ヒ', 'ﾋ', segF | segE | segD | segG},
+	{'フ', 'ﾌ', segA | segB | segC},
+	{'ヘ', 'ﾍ', segE | segG | segC},
+	{'ホ', 'ﾎ', segA | segG | segE | segC},
+	{'マ', 'ﾏ', segA | segB | segG | segC},
+	{'ミ', 'ﾐ', segA | segG | segD},
+	{'ム', 'ﾑ', segE | segD | segC},
+	{'メ', 'ﾒ', segB | segG | segE},
+	{'モ', 'ﾓ', segA | segG | segF | segE | segD},
+	{'ヤ', 'ﾔ', segF | segG | segB | segE},
+	{'ユ', 'ﾕ', segB | segC | segD},
+	{'ヨ', 'ﾖ', segA | segB | segG | segC | segD},
+	{'ラ', 'ﾗ', segA | segG | segB | segC},
+	{'リ', 'ﾘ', segF | segE | segB | segC},
+	{'ル', 'ﾙ', segF | segE | segC | segD},
+	{'レ', 'ﾚ', segF | segE | segD},
+	{'ロ', 'ﾛ', segA | segB | segC | segD | segE | segF},
+	{'ワ', 'ﾜ', segF | segA | segB | segC},
+	{'ヲ', 'ｦ', segA | segG | segB | segC | segE},
+	{'ン', 'ﾝ', segF | segC | segD},
+	{'ー', 'ｰ', segG}, // Long vowel mark
+}
+
+// Glyph returns the pattern of r, falling back to DefaultFont.
+//
+// Glyphは、rのパターンを返す。見つからなければDefaultFontから探す。
+func (katakanaFont) Glyph(r rune) (byte, bool) {
+	for _, k := range katakanaGlyphs {
+		if r == k.full || r == k.half {
+			return k.pattern, true
+		}
+	}
+	return DefaultFont.Glyph(r)
+}
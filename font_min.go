@@ -0,0 +1,30 @@
+//go:build ht16k33_minfont
+
+package ht16k33
+
+// asciiGlyphs is the numeric-only table used with the ht16k33_minfont tag.
+// It ends at '9' so that no flash is spent on letters.
+//
+// asciiGlyphsは、ht16k33_minfontタグを付けた場合に使う数字だけの表。
+// 英字にフラッシュを使わないよう'9'で終わる。
+var asciiGlyphs = [...]byte{
+	'0': segA | segB | segC | segD | segE | segF,
+	'1': segB | segC,
+	'2': segA | segB | segG | segE | segD,
+	'3': segA | segB | segG | segC | segD,
+	'4': segF | segG | segB | segC,
+	'5': segA | segF | segG | segC | segD,
+	'6': segA | segF | segE | segD | segC | segG,
+	'7': segA | segB | segC,
+	'8': segA | segB | segC | segD | segE | segF | segG,
+	'9': segA | segB | segC | segD | segF | segG,
+	' ': 0, // Space
+	'-': segG,
+}
+
+// symbolGlyph has no symbols in the minimal font.
+//
+// symbolGlyphは、最小フォントでは記号を持たない。
+func symbolGlyph(r rune) (byte, bool) {
+	return 0, false
+}
@@ -0,0 +1,19 @@
+//go:build ht16k33_minfont
+
+package ht16k33
+
+import "testing"
+
+// TestMinimalFont verifies that the minimal font only has the numeric glyphs.
+func TestMinimalFont(t *testing.T) {
+	for _, r := range "0123456789- " {
+		if _, ok := DefaultFont.Glyph(r); !ok {
+			t.Errorf("FAIL: %q should be in the minimal font", r)
+		}
+	}
+	for _, r := range "AZ_°" {
+		if _, ok := DefaultFont.Glyph(r); ok {
+			t.Errorf("FAIL: %q should not be in the minimal font", r)
+		}
+	}
+}
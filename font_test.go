@@ -0,0 +1,50 @@
+package ht16k33
+
+import (
+	"bytes"
+	"testing"
+)
+
+// numericFont is a Font that only knows the digit 1, used to test SetFont.
+type numericFont struct{}
+
+func (numericFont) Glyph(r rune) (byte, bool) {
+	if r == '1' {
+		return segB | segC, true
+	}
+	return 0, false
+}
+
+// TestSetFont verifies that the font can be swapped at any time.
+func TestSetFont(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetFont(numericFont{})
+	device.WriteString(0, "A1")
+
+	expectedBuffer := [16]byte{1: 1 << 1, 2: 1 << 1}
+	if !bytes.Equal(device.buffer[:], expectedBuffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedBuffer[:], device.buffer[:])
+	}
+
+	device.SetFont(nil)
+	if _, ok := device.font.Glyph('8'); !ok {
+		t.Errorf("FAIL: SetFont(nil) should restore the default font")
+	}
+}
+
+// TestKatakanaFont verifies that both kana forms and the fallback work.
+func TestKatakanaFont(t *testing.T) {
+	for _, k := range katakanaGlyphs {
+		full, ok1 := KatakanaFont.Glyph(k.full)
+		half, ok2 := KatakanaFont.Glyph(k.half)
+		if !ok1 || !ok2 || full != half || full == 0 {
+			t.Errorf("FAIL: %q and %q should have the same non-blank glyph", k.full, k.half)
+		}
+	}
+	if _, ok := KatakanaFont.Glyph('7'); !ok {
+		t.Errorf("FAIL: KatakanaFont should fall back to DefaultFont for digits")
+	}
+	if _, ok := KatakanaFont.Glyph('あ'); ok {
+		t.Errorf("FAIL: Hiragana should not be in KatakanaFont")
+	}
+}
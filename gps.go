@@ -0,0 +1,63 @@
+package ht16k33
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidCoordinate is returned by WriteCoordinates for a latitude
+// outside -90 to 90 or a longitude outside -180 to 180.
+var ErrInvalidCoordinate = errors.New("ht16k33: invalid coordinate")
+
+// WriteCoordinates shows a GPS position across the 16 digits of both
+// displays, in degrees and decimal minutes with a hemisphere letter instead
+// of a sign: the latitude on display A as "35.41.123N" and the longitude on
+// display B as "139.41.12E". The DP after the degrees separates them from
+// the minutes. It needs two 8-digit displays; otherwise the displays are
+// left unchanged and ErrOverflow is returned.
+//
+// WriteCoordinatesは、両方のディスプレイの16桁にGPSの位置を表示する。度と
+// 10進の分で表し、符号の代わりに半球の文字を付ける。ディスプレイAに緯度を
+// "35.41.123N"、ディスプレイBに経度を"139.41.12E"のように表示する。度の後の
+// DPで分と区切る。8桁のディスプレイが2つ必要で、そうでなければディスプレイ
+// を変更せずにErrOverflowを返す。
+func (d *Device) WriteCoordinates(lat, lon float64) error {
+	if !(lat >= -90 && lat <= 90) || !(lon >= -180 && lon <= 180) {
+		return ErrInvalidCoordinate
+	}
+	if d.displays != NumDisplays || d.digits != MaxDigitsPerDisplay {
+		return ErrOverflow
+	}
+	latCells, _, err := d.layoutString(formatCoordinate(lat, 2, 3, 'N', 'S'))
+	if err != nil {
+		return err
+	}
+	lonCells, _, err := d.layoutString(formatCoordinate(lon, 3, 2, 'E', 'W'))
+	if err != nil {
+		return err
+	}
+	d.clearAll()
+	d.writeCells(0, 0, latCells)
+	d.writeCells(1, 0, lonCells)
+	d.autoDisplay()
+	return nil
+}
+
+// formatCoordinate formats v as degrees zero-padded to degreeDigits, a '.',
+// minutes with decimals digits after the decimal point, and the hemisphere
+// letter.
+//
+// formatCoordinateは、vをdegreeDigits桁に0で埋めた度、'.'、小数点以下
+// decimals桁の分、半球の文字に整形する。
+func formatCoordinate(v float64, degreeDigits int, decimals int, positive, negative byte) string {
+	hemisphere := positive
+	if v < 0 {
+		hemisphere = negative
+	}
+	// Round in whole units of the last minute digit, so that 59.9999
+	// minutes carries into the degrees.
+	scale := int64(math.Pow10(decimals))
+	total := int64(math.Round(math.Abs(v) * 60 * float64(scale)))
+	degrees, minutes := total/(60*scale), total%(60*scale)
+	return zeroPad(degrees, degreeDigits) + "." + joinDecimal("", zeroPad(minutes, decimals+2), decimals) + string(hemisphere)
+}
@@ -0,0 +1,39 @@
+package ht16k33
+
+import "testing"
+
+// TestFormatCoordinate verifies the degrees, minutes and hemisphere.
+func TestFormatCoordinate(t *testing.T) {
+	tests := []struct {
+		v        float64
+		expected string
+	}{
+		{35.685, "35.41.100N"},
+		{-33.8688, "33.52.128S"},
+		{5.99999999, "06.00.000N"},
+		{0, "00.00.000N"},
+	}
+	for _, test := range tests {
+		if got := formatCoordinate(test.v, 2, 3, 'N', 'S'); got != test.expected {
+			t.Errorf("FAIL: formatCoordinate(%v) is wrong!\nExpected: %s\nGot:      %s", test.v, test.expected, got)
+		}
+	}
+}
+
+// TestWriteCoordinates verifies the layout across both displays.
+func TestWriteCoordinates(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	if err := device.WriteCoordinates(35.685, -139.7514); err != nil {
+		t.Fatalf("WriteCoordinates() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "35.41.100N")
+	assertDisplay(t, device, 1, "139.45.08W")
+
+	if err := device.WriteCoordinates(91, 0); err != ErrInvalidCoordinate {
+		t.Errorf("FAIL: WriteCoordinates(91, 0) should return ErrInvalidCoordinate, got %v", err)
+	}
+	if err := device.WriteCoordinates(0, -180.5); err != ErrInvalidCoordinate {
+		t.Errorf("FAIL: WriteCoordinates(0, -180.5) should return ErrInvalidCoordinate, got %v", err)
+	}
+}
@@ -7,7 +7,7 @@
 //
 // The driver provides two ways to interact with the displays:
 //  1. As two independent 8-digit displays (`SetDigitOnDisplay`, `WriteString`).
-//  2. As a single, continuous 16-digit display (`SetDigit16`).
+//  2. As a single, continuous 16-digit display (`SetDigit16`, `WriteString16`).
 //
 // Wiring Overview:
 // This driver utilizes a clever multiplexing technique to drive 16 digits
@@ -40,18 +40,59 @@
 //     of Display B.
 package ht16k33
 
-import "time"
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+)
 
 const (
 	// Commands for HT16K33
-	ht16k33TurnOnOscillator = 0x21
-	ht16k33TurnOnDisplay    = 0x81
-	ht16k33SetBrightness    = 0xE0
+	ht16k33TurnOffOscillator = 0x20
+	ht16k33TurnOnOscillator  = 0x21
+	ht16k33DisplaySetup      = 0x80
+	ht16k33RowIntSet         = 0xA0
+	ht16k33SetBrightness     = 0xE0
+	// keyDataAddress is the first address of the key data RAM.
+	keyDataAddress = 0x40
+	// interruptFlagAddress is the address of the INT flag register.
+	interruptFlagAddress = 0x60
 
 	// MaxDigitsPerDisplay is the number of 7-segment digits per display unit.
 	MaxDigitsPerDisplay = 8
 	// NumDisplays is the number of display units driven by one HT16K33.
 	NumDisplays = 2
+
+	// segmentRows is the number of ROW lines (a-g and dp) used by one display.
+	segmentRows = 8
+
+	// MinAddress and MaxAddress are the I2C addresses selectable with the
+	// A0-A2 pins of the HT16K33.
+	MinAddress = 0x70
+	MaxAddress = 0x77
+)
+
+var (
+	// ErrNilBus is returned by New when no I2C bus is given.
+	ErrNilBus = errors.New("ht16k33: nil I2C bus")
+	// ErrInvalidAddress is returned by New when the address is outside 0x70-0x77.
+	ErrInvalidAddress = errors.New("ht16k33: I2C address out of range (0x70-0x77)")
+	// ErrInvalidGeometry is returned by New when WithGeometry was given a
+	// layout the HT16K33 cannot drive.
+	ErrInvalidGeometry = errors.New("ht16k33: invalid display geometry")
+	// ErrVerifyFailed is returned by Configure when the display RAM read back
+	// from the chip does not match what was written.
+	ErrVerifyFailed = errors.New("ht16k33: display RAM verification failed")
+	// ErrInvalidDisplay is returned when a display number is out of range.
+	ErrInvalidDisplay = errors.New("ht16k33: display out of range")
+	// ErrInvalidPeriod is returned by AddDimmingPeriod when a time of day is
+	// outside 0-24h.
+	ErrInvalidPeriod = errors.New("ht16k33: time of day out of range (0-24h)")
+	// ErrUnknownPreset is returned by ApplyPreset for a name that is neither
+	// a default preset nor set with SetPreset.
+	ErrUnknownPreset = errors.New("ht16k33: unknown brightness preset")
+	// ErrInvalidKey is returned when a key row or scan line is out of range.
+	ErrInvalidKey = errors.New("ht16k33: key out of range")
 )
 
 // fadeState represents the current state of the non-blocking fade effect.
@@ -63,74 +104,6 @@ const (
 	fadeStateIn
 )
 
-// --- 7-Segment Font Definition ---
-
-// The segments are mapped to bits in a byte, following the common g-f-e-d-c-b-a order.
-// セグメントは一般的な g-f-e-d-c-b-a の順でバイト内のビットにマッピングされている。
-//
-//	 a
-//	---
-//
-// f|g|b
-//
-//	---
-//
-// e| |c
-//
-//	---
-//	 d
-const (
-	segA byte = 1 << 0
-	segB byte = 1 << 1
-	segC byte = 1 << 2
-	segD byte = 1 << 3
-	segE byte = 1 << 4
-	segF byte = 1 << 5
-	segG byte = 1 << 6
-)
-
-// font maps a rune to its 7-segment pattern. This visual representation makes
-// it much easier to add or modify characters.
-// fontは、ルーン文字を7セグメントのパターンにマッピングする。
-// 視覚的にどのセグメントが光るのかをわかりやすく表現している。
-var font = map[rune]byte{
-	'0':  segA | segB | segC | segD | segE | segF,
-	'1':  segB | segC,
-	'2':  segA | segB | segG | segE | segD,
-	'3':  segA | segB | segG | segC | segD,
-	'4':  segF | segG | segB | segC,
-	'5':  segA | segF | segG | segC | segD,
-	'6':  segA | segF | segE | segD | segC | segG,
-	'7':  segA | segB | segC,
-	'8':  segA | segB | segC | segD | segE | segF | segG,
-	'9':  segA | segB | segC | segD | segF | segG,
-	'A':  segA | segB | segC | segE | segF | segG,
-	'B':  segF | segE | segD | segC | segG, // Lowercase 'b'
-	'C':  segA | segF | segE | segD,
-	'D':  segB | segC | segD | segE | segG, // Lowercase 'd'
-	'E':  segA | segF | segG | segE | segD,
-	'F':  segA | segF | segG | segE,
-	'G':  segA | segF | segE | segD | segC,
-	'H':  segF | segE | segG | segB | segC,
-	'I':  segB | segC, // Same as 1
-	'J':  segB | segC | segD | segE,
-	'L':  segF | segE | segD,
-	'O':  segA | segB | segC | segD | segE | segF, // Same as 0
-	'P':  segA | segB | segG | segF | segE,
-	'Q':  segA | segB | segC | segF | segG,
-	'R':  segE | segG,                      // Lowercase 'r'
-	'S':  segA | segF | segG | segC | segD, // Same as 5
-	'U':  segB | segC | segD | segE | segF,
-	'Y':  segF | segG | segB | segC | segD,
-	' ':  0, // Space
-	'-':  segG,
-	'_':  segD,
-	'\'': segB,
-	'"':  segB | segF,
-	'=':  segD | segG,
-	'?':  segA | segB | segG | segE,
-}
-
 // I2CBus is an interface that abstracts the I2C Tx method we need.
 //
 // I2CBusは、必要とするI2CのTxメソッドを抽象化するインターフェース
@@ -144,43 +117,368 @@ type I2CBus interface {
 type Device struct {
 	bus     I2CBus
 	Address uint8
+	// clock gives the time to the non-blocking effects (see WithClock).
+	// clockは、ノンブロッキングの効果に時刻を与える(WithClockを参照)。
+	clock Clock
 	// Display RAM buffer for the HT16K33 (16x8 bits).
 	// HT16K33の表示用RAMバッファ(16x8ビット)
 	buffer [16]byte
 	// currentBrightness holds the current brightness level (0-15).
 	// currentBrightnessは、現在の明るさのレベル(0-15)を保持する。
 	currentBrightness uint8
+	// font is used to look up the pattern of each character.
+	// fontは、各文字のパターンを探すために使う。
+	font Font
+	// displays and digits describe the wiring (see WithGeometry).
+	// displaysとdigitsは、結線の構成を表す(WithGeometryを参照)。
+	displays int
+	digits   int
+	// autoFlush makes every buffer change call Display immediately.
+	// autoFlushがtrueなら、バッファを変更するたびにDisplayを呼ぶ。
+	autoFlush bool
+	// unknownRunes and fallbackGlyph decide how characters missing from the
+	// font are rendered (see SetUnknownRunePolicy).
+	// unknownRunesとfallbackGlyphは、フォントにない文字の描画方法を決める
+	// (SetUnknownRunePolicyを参照)。
+	unknownRunes  UnknownRunePolicy
+	fallbackGlyph byte
+	// decimalComma makes ',' a decimal point like '.'.
+	// decimalCommaがtrueなら、','を'.'と同じく小数点として扱う。
+	decimalComma bool
+	// colonPolicy and colons decide how ':' is rendered (see SetColonPolicy).
+	// colonPolicyとcolonsは、':'の描画方法を決める(SetColonPolicyを参照)。
+	colonPolicy ColonPolicy
+	colons      [NumDisplays]colonOutput
+	// alignment decides where short strings are placed.
+	// alignmentは、短い文字列を置く位置を決める。
+	alignment Alignment
+	// overflow decides how values that do not fit are shown.
+	// overflowは、収まらない値の表示方法を決める。
+	overflow OverflowStrategy
+	// overflowIndicator is shown for values that do not fit with
+	// OverflowIndicator.
+	// overflowIndicatorは、OverflowIndicatorで収まらない値の代わりに表示する。
+	overflowIndicator string
+	// sign decides where the minus sign of negative numbers is shown.
+	// signは、負の数のマイナス記号を表示する位置を決める。
+	sign SignPlacement
+	// padding decides what fills the digits left of numbers.
+	// paddingは、数値の左側の桁を何で埋めるかを決める。
+	padding Padding
+	// grouping shows thousands separators in integers.
+	// groupingは、整数に3桁区切りを表示する。
+	grouping bool
+	// notation decides how WriteFloat writes numbers.
+	// notationは、WriteFloatで数値をどう書くかを決める。
+	notation Notation
+	// rounding decides how values are rounded to the digits shown.
+	// roundingは、表示する桁に値をどう丸めるかを決める。
+	rounding RoundingMode
+	// rangeSet, rangeMin and rangeMax limit the values shown (see SetRange),
+	// and rangeLo and rangeHi are shown outside them.
+	// rangeSet、rangeMin、rangeMaxは表示する値を制限し(SetRangeを参照)、
+	// その範囲外ではrangeLoとrangeHiを表示する。
+	rangeSet           bool
+	rangeMin, rangeMax float64
+	rangeLo, rangeHi   string
+	// rangeFixedMin and rangeFixedMax are the range scaled for WriteFixed
+	// with rangeDecimals decimals, or -1 before they are worked out.
+	// rangeFixedMinとrangeFixedMaxは、WriteFixedのためにrangeDecimals桁の
+	// 小数に合わせた範囲。計算する前はrangeDecimalsが-1。
+	rangeDecimals                int
+	rangeFixedMin, rangeFixedMax int64
+	// percentStyle decides how WritePercent shows the percent sign.
+	// percentStyleは、WritePercentでパーセント記号をどう表示するかを決める。
+	percentStyle PercentStyle
+	// disabledDisplays has bit n set when display n is masked on flush.
+	// disabledDisplaysは、ディスプレイnを転送時に消す場合にビットnが立つ。
+	disabledDisplays uint8
+	// displayOn reports whether the display has been turned on.
+	// displayOnは、ディスプレイがオンになっているかを表す。
+	displayOn bool
+	// hardwareBlink is the blink rate sent with the display setup.
+	// hardwareBlinkは、表示設定と一緒に送る点滅の速さ。
+	hardwareBlink BlinkRate
+	// interruptMode is the use of ROW15/INT set with SetInterruptMode.
+	// interruptModeは、SetInterruptModeで設定したROW15/INTの使い方。
+	interruptMode InterruptMode
+	// oscillatorOn is set once Configure has turned the oscillator on, and
+	// standby is set between Standby and Wake.
+	// oscillatorOnはConfigureがオシレーターをオンにしたら設定し、standbyは
+	// StandbyからWakeまでの間に設定する。
+	oscillatorOn bool
+	standby      bool
+	// chip is the setup last sent to the chip (see ApplySetup), which is
+	// sent again in full while chipUnknown is set.
+	// chipはチップに最後に送った設定(ApplySetupを参照)で、chipUnknownが
+	// 設定されている間は全体を再び送る。
+	chip        chipSetup
+	chipUnknown bool
+	// stats counts the I2C transactions (see Stats).
+	// statsは、I2Cの通信を数える(Statsを参照)。
+	stats Stats
+
+	// --- For non-blocking animations ---
+	// animator runs the animations added with Animate, and fade, breath and
+	// ramp are the ones started by StartFade, StartBreathing and
+	// SetBrightnessSmooth.
+	// animatorはAnimateで追加したアニメーションを動かし、fade、breath、
+	// rampはStartFade、StartBreathing、SetBrightnessSmoothで始めたもの。
+	animator Animator
+	fade     *fadeAnimation
+	breath   *breathAnimation
+	ramp     *rampAnimation
+	// fadedOut is set by StartFadeOut until StartFadeIn, which fades in to
+	// fadeRestore.
+	// fadedOutはStartFadeOutで設定してStartFadeInまで残り、StartFadeInは
+	// fadeRestoreまでフェードインする。
+	fadedOut    bool
+	fadeRestore uint8 // In quarter steps, as GetBrightnessFine
+	// lastAnimation is the animation added last, for OnDone.
+	// lastAnimationは、OnDoneのために最後に追加したアニメーション。
+	lastAnimation Animation
+	// paused is set between Pause and Resume, and pausedTime is the time
+	// spent paused before, which the animations do not see.
+	// pausedはPauseからResumeまでの間設定し、pausedTimeはそれまでに止めて
+	// いた時間で、アニメーションからは見えない。
+	paused     bool
+	pausedAt   uint32
+	pausedTime uint32
+
+	// --- For the blinking separator of WriteTime ---
+	// separators has the buffer bits of the separators written by WriteTime.
+	// separatorsは、WriteTimeで書き込んだ区切りのバッファのビットを持つ。
+	separators     [16]byte
+	separatorBlink bool
+	separatorsOff  bool
+	blinkStart     uint32
+
+	// --- For non-blocking scrolling ---
+	scrolls [NumDisplays]scrollState
+
+	// --- For non-blocking flips ---
+	flips [NumDisplays]flipState
+
+	// --- For transitions ---
+	// transitions has the running transition of each display, so that a new
+	// one can replace it.
+	// transitionsは、各ディスプレイで動いているトランジションを持ち、新しい
+	// ものがそれを置き換えられるようにする。
+	transitions [NumDisplays]*transition
+
+	// --- For decorative effects ---
+	spinners []*spinnerAnimation
+	snakes   [NumDisplays]*snakeAnimation
+	scanners [NumDisplays]*scannerAnimation
+	sparkles [NumDisplays]*sparkleAnimation
+
+	// --- For the digit blink of SetBlink ---
+	// blinkDigits has bit n of display m set when digit n of it blinks.
+	// blinkDigitsは、ディスプレイmの桁nが点滅する場合にビットnが立つ。
+	blinkDigits     [NumDisplays]byte
+	blinkPeriod     uint32
+	digitBlinkStart uint32
+	digitsOff       bool
+
+	// --- For the edit cursor of SetCursor ---
+	cursorOn       bool
+	cursorDisplay  int
+	cursorPosition int
+	cursorPeriod   uint32
+	cursorStart    uint32
+	cursorShown    bool
+
+	// --- For StartFlash ---
+	// flashOff is set in the off half of a flash, which frame applies.
+	// flashOffは点滅の消灯側で設定し、frameがそれを適用する。
+	flash      *flashAnimation
+	flashOff   bool
+	flashStyle FlashStyle
+
+	// --- For the dithered brightness of SetBrightnessFine ---
+	// ditherFraction is the quarter level above ditherBase (0 when not
+	// dithering), and ditherError carries it over from Update to Update.
+	// ditherFractionはditherBaseより上の1/4段階(ディザリングしないときは0)で、
+	// ditherErrorはそれをUpdateから次のUpdateへ持ち越す。
+	ditherBase     uint8
+	ditherFraction int
+	ditherError    int
+
+	// --- For the dimming schedule of AddDimmingPeriod ---
+	// schedulePeriod is the index of the period in effect, or -1 outside
+	// them, and scheduleRestore is the brightness from before it.
+	// schedulePeriodは有効な期間のインデックスで、期間外なら-1。
+	// scheduleRestoreはその前の明るさ。
+	schedule        []dimmingPeriod
+	schedulePeriod  int
+	scheduleRestore uint8
 
-	// --- For non-blocking fade ---
-	fadeState      fadeState
-	fadeStep       int
-	lastUpdateTime time.Time
-	fadeDelay      time.Duration
+	// --- For SetBrightnessSource ---
+	// sourceAverage is the smoothed reading and sourceLevel the brightness
+	// it gives, or -1 before the first reading.
+	// sourceAverageは平滑化した読み取り値で、sourceLevelはそれによる明るさ。
+	// 最初の読み取りの前は-1。
+	brightnessSource func() uint16
+	sourceAverage    int
+	sourceLevel      int
+	sourceLast       uint32
+
+	// --- For the screensaver of SetIdleTimeout ---
+	// idle is set while the screensaver is on, and idleOff while it blanks
+	// the displays, which frame applies.
+	// idleはスクリーンセーバーが有効な間に設定し、idleOffはディスプレイを
+	// 消している間に設定し、frameがそれを適用する。
+	idleTimeout  uint32
+	idleAction   IdleAction
+	lastActivity uint32
+	idle         bool
+	idleOff      bool
+	idleRestore  uint8
+
+	// --- For the emulated brightness of SetDisplayBrightness ---
+	// displayDim is how many levels below full each display is, and
+	// dutyOff has bit n set while display n is off in its duty cycle,
+	// which frame applies.
+	// displayDimは各ディスプレイが最大から何段階下かを表し、dutyOffは
+	// ディスプレイnがデューティサイクルの消灯側にある間ビットnが立ち、
+	// frameがそれを適用する。
+	displayDim [NumDisplays]uint8
+	dutyError  [NumDisplays]int
+	dutyOff    byte
+
+	// --- For the brightness presets of ApplyPreset ---
+	// presets holds the presets set with SetPreset, and presetOff is set
+	// while PresetOff blanks the displays, which frame applies.
+	// presetsはSetPresetで設定したプリセットを保持し、presetOffはPresetOffが
+	// ディスプレイを消している間に設定し、frameがそれを適用する。
+	presets   []preset
+	presetOff bool
+
+	// --- For the key events of PollKeys ---
+	// keyCandidate is the last raw read, seen since keyChanged, which
+	// becomes keyStable once it has not changed for the debounce time.
+	// keyCandidateは最後に読んだ生の値でkeyChangedから変わっておらず、
+	// デバウンス時間変わらなければkeyStableになる。
+	keyStable      Keys
+	keyCandidate   Keys
+	keyChanged     uint32
+	keyDebounce    uint32
+	keyHoldDelay   uint32
+	keyRepeatDelay uint32
+	keyNames       []keyName
+	keyHolds       []keyHold
+	keyEvents      []KeyEvent
+	// keyPending is set by KeyInterrupt, possibly from an interrupt
+	// handler, and makes the next PollKeys read the keys when
+	// keyInterruptDriven is set.
+	// keyPendingはKeyInterruptが(割り込みハンドラからでも)設定し、
+	// keyInterruptDrivenが設定されていれば次のPollKeysでキーを読ませる。
+	keyInterruptDriven bool
+	keyPending         atomic.Bool
 }
 
 // New creates a new Device instance.
+// It returns an error if bus is nil or address is not in the range
+// 0x70-0x77. The Device is returned as a pointer so that its buffer and fade
+// state are never copied by accident. Options such as WithInitialBrightness
+// can be given to customize the Device.
 //
 // Newは、新しいDeviceインスタンスを作る
-func New(bus I2CBus, address uint8) Device {
-	return Device{
+// busがnil、またはaddressが0x70-0x77の範囲外の場合はエラーを返す。
+// バッファやフェードの状態が誤ってコピーされないよう、ポインタで返す。
+// WithInitialBrightnessなどのオプションでDeviceをカスタマイズできる。
+func New(bus I2CBus, address uint8, opts ...Option) (*Device, error) {
+	if bus == nil {
+		return nil, ErrNilBus
+	}
+	if address < MinAddress || address > MaxAddress {
+		return nil, ErrInvalidAddress
+	}
+	d := &Device{
 		bus:               bus,
 		Address:           address,
 		currentBrightness: 15, // Default to max brightness
-		fadeState:         fadeStateIdle,
+		font:              DefaultFont,
+		displays:          NumDisplays,
+		digits:            MaxDigitsPerDisplay,
+		fallbackGlyph:     segD,
+		schedulePeriod:    -1,
+	}
+	for _, opt := range opts {
+		opt(d)
 	}
+	if d.displays < 1 || d.displays > NumDisplays || d.digits < 1 || d.digits > MaxDigitsPerDisplay {
+		return nil, ErrInvalidGeometry
+	}
+	if d.font == nil {
+		d.font = DefaultFont
+	}
+	return d, nil
+}
+
+// Config holds the settings applied by Configure.
+//
+// Configは、Configureで適用する設定を保持する。
+type Config struct {
+	// Brightness is the brightness level (1-15) set after initialization.
+	// Zero keeps the current level (15 unless WithInitialBrightness was given).
+	// Brightnessは、初期化後に設定する明るさ(1-15)。
+	// 0なら現在の明るさ(WithInitialBrightnessを指定しなければ15)のまま。
+	Brightness uint8
+	// ClearRAM clears the buffer and the display RAM of the chip.
+	// ClearRAMは、バッファとチップの表示用RAMをクリアする。
+	ClearRAM bool
+	// Verify reads the display RAM back after clearing it to confirm that
+	// the chip actually responded. It implies ClearRAM.
+	// Verifyは、クリア後に表示用RAMを読み戻して、チップが本当に応答したかを
+	// 確認する。ClearRAMも有効になる。
+	Verify bool
 }
 
 // Configure initializes the HT16K33 device.
-// It turns on the oscillator and the display, and sets the brightness to
-// maximum.
+// It turns on the oscillator and the display, and sets the brightness.
+// It returns the first I2C error, or ErrVerifyFailed if cfg.Verify is set
+// and the display RAM could not be confirmed to be cleared.
 //
 // Configureは、HT16K33デバイスを初期化する
-// オシレーターとディスプレイをオンにし、明るさを最大に設定する。
-func (d *Device) Configure() {
-	d.bus.Tx(uint16(d.Address), []byte{ht16k33TurnOnOscillator}, nil)
-	d.bus.Tx(uint16(d.Address), []byte{ht16k33TurnOnDisplay}, nil)
-	// Set to maximum brightness for now
-	d.SetBrightness(15)
+// オシレーターとディスプレイをオンにし、明るさを設定する。
+// 最初に起きたI2Cのエラーを返す。cfg.Verifyを指定して表示用RAMのクリアを
+// 確認できなかった場合はErrVerifyFailedを返す。
+func (d *Device) Configure(cfg Config) error {
+	d.oscillatorOn = true
+	d.displayOn = true
+	brightness := d.currentBrightness
+	if cfg.Brightness != 0 {
+		brightness = cfg.Brightness
+	}
+	if brightness > 15 {
+		brightness = 15
+	}
+	d.currentBrightness = brightness
+	// The chip may not be as it was left, so send the whole setup.
+	d.chipUnknown = true
+	if err := d.ApplySetup(); err != nil {
+		return err
+	}
+
+	if !cfg.ClearRAM && !cfg.Verify {
+		return nil
+	}
+	d.clearAll()
+	if err := d.tx(append([]byte{0x00}, d.buffer[:]...), nil); err != nil {
+		return err
+	}
+	if !cfg.Verify {
+		return nil
+	}
+	var ram [16]byte
+	if err := d.tx([]byte{0x00}, ram[:]); err != nil {
+		return err
+	}
+	if ram != d.buffer {
+		return ErrVerifyFailed
+	}
+	return nil
 }
 
 // ClearAll clears the entire display buffer, turning off all segments on
@@ -189,8 +487,15 @@ func (d *Device) Configure() {
 // ClearAllは、表示バッファ全体をクリアし、両方のディスプレイの全セグメン
 // トを消灯させる。
 func (d *Device) ClearAll() {
+	d.clearAll()
+	d.autoDisplay()
+}
+
+// clearAll is ClearAll without the auto flush.
+func (d *Device) clearAll() {
 	for i := range d.buffer {
 		d.buffer[i] = 0
+		d.separators[i] = 0
 	}
 }
 
@@ -201,15 +506,40 @@ func (d *Device) ClearAll() {
 //
 // display: 0 for the first display (A), 1 for the second (B)
 // position: 0-7, the digit position
-// char: The character to display. If not in the font map, it will be blank.
+// char: The character to display. If not in the font map, it is handled
+// according to the UnknownRunePolicy (blank by default).
 // dot: true to light up the decimal point
-func (d *Device) SetDigitOnDisplay(display int, position int, char rune, dot bool) {
-	pattern, ok := font[char]
-	if !ok {
-		// If the character is not in the font map, use a blank pattern.
-		pattern = font[' ']
+func (d *Device) SetDigitOnDisplay(display int, position int, char rune, dot bool) error {
+	err := d.setChar(display, position, char, dot)
+	d.autoDisplay()
+	return err
+}
+
+// setChar is SetDigitOnDisplay without the auto flush.
+func (d *Device) setChar(display int, position int, char rune, dot bool) error {
+	pattern, skip, err := d.resolveRune(char)
+	if skip || err != nil {
+		return err
 	}
 	d.setPattern(display, position, pattern, dot)
+	return nil
+}
+
+// SetDigitOnDisplayOverlay is like SetDigitOnDisplay, but adds the segments
+// of char to the digit instead of replacing it, so a glyph can be combined
+// with segments drawn by other code.
+//
+// SetDigitOnDisplayOverlayはSetDigitOnDisplayと同様だが、桁を置き換えずに
+// charのセグメントを追加するので、他のコードで描いたセグメントとグリフを
+// 組み合わせられる。
+func (d *Device) SetDigitOnDisplayOverlay(display int, position int, char rune, dot bool) error {
+	pattern, skip, err := d.resolveRune(char)
+	if skip || err != nil {
+		return err
+	}
+	d.orPattern(display, position, pattern, dot)
+	d.autoDisplay()
+	return nil
 }
 
 // SetDigit16 treats the two 8-digit displays as a single 16-digit display.
@@ -221,14 +551,14 @@ func (d *Device) SetDigitOnDisplay(display int, position int, char rune, dot boo
 // position: 0-15, the digit position across both displays.
 // char: The character to display.
 // dot: true to light up the decimal point.
-func (d *Device) SetDigit16(position int, char rune, dot bool) {
-	if position < 0 || position >= MaxDigitsPerDisplay*NumDisplays {
-		return // 0-15の範囲外なら何もしない
+func (d *Device) SetDigit16(position int, char rune, dot bool) error {
+	if position < 0 || position >= d.digits*d.displays {
+		return nil // 0-15の範囲外なら何もしない
 	}
 
-	display := position / MaxDigitsPerDisplay        // 0-7 -> 0, 8-15 -> 1
-	digitInDisplay := position % MaxDigitsPerDisplay // 8 -> 0, 9 -> 1, ...
-	d.SetDigitOnDisplay(display, digitInDisplay, char, dot)
+	display := position / d.digits        // 0-7 -> 0, 8-15 -> 1
+	digitInDisplay := position % d.digits // 8 -> 0, 9 -> 1, ...
+	return d.SetDigitOnDisplay(display, digitInDisplay, char, dot)
 }
 
 // ClearOnDisplay clears one of the two 8-digit displays.
@@ -236,11 +566,21 @@ func (d *Device) SetDigit16(position int, char rune, dot bool) {
 //
 // ClearOnDisplayは、2つの8桁ディスプレイのうちの1つをクリアする。
 func (d *Device) ClearOnDisplay(display int) {
-	if display < 0 || display >= NumDisplays {
+	if display < 0 || display >= d.displays {
 		return
 	}
-	for pos := 0; pos < MaxDigitsPerDisplay; pos++ {
-		d.setPattern(display, pos, font[' '], false)
+	d.clearDisplay(display)
+	d.autoDisplay()
+}
+
+// clearDisplay is ClearOnDisplay without the auto flush.
+func (d *Device) clearDisplay(display int) {
+	for pos := 0; pos < d.digits; pos++ {
+		d.setPattern(display, pos, 0, false)
+	}
+	d.setColon(display, false)
+	for i := 0; i < segmentRows; i++ {
+		d.separators[display*segmentRows+i] = 0
 	}
 }
 
@@ -250,10 +590,10 @@ func (d *Device) ClearOnDisplay(display int) {
 // ClearFadeOnDisplayは、フェード効果付きで2つの8桁ディスプレイのうちの1つをクリアする。
 // バッファ内のディスプレイをクリアした後、フェードアウト/フェードインを実行する。
 func (d *Device) ClearFadeOnDisplayBlocking(display int, delay time.Duration) {
-	if display < 0 || display >= NumDisplays {
+	if display < 0 || display >= d.displays {
 		return
 	}
-	d.ClearOnDisplay(display)    // Clear the relevant part of the buffer
+	d.clearDisplay(display)      // Clear the relevant part of the buffer
 	d.DisplayFadeBlocking(delay) // Apply the fade effect to show the change
 }
 
@@ -261,58 +601,66 @@ func (d *Device) ClearFadeOnDisplayBlocking(display int, delay time.Duration) {
 //
 // ClearAllFadeは、フェード効果付きで両方のディスプレイをクリアする。
 func (d *Device) ClearAllFadeBlocking(delay time.Duration) {
-	d.ClearAll()
+	d.clearAll()
 	d.DisplayFadeBlocking(delay)
 }
 
-// WriteString displays a string on one of the two displays.
-// It clears the target display before writing.
+// SetSegments sets a raw segment pattern at a position on one of the two
+// displays, for shapes that are not in the font.
 //
-// WriteStringは、2つのディスプレイのいずれかに文字列を表示する。
+// SetSegmentsは、2つのディスプレイのいずれかの指定した位置に、生のセグメント
+// パターンを設定する。フォントにない形を描くために使う。
 //
-// display: 0 for the first display (A), 1 for the second (B).
-// s: The string to display. Handles numbers and dots (e.g., "123", "45.6", "78.").
-func (d *Device) WriteString(display int, s string) {
-	if display < 0 || display >= NumDisplays {
-		return
-	}
-
-	d.ClearOnDisplay(display)
+// display: 0 for the first display (A), 1 for the second (B)
+// position: 0-7, the digit position
+// pattern: bits 0-6 light up segments a-g (bit 7 is ignored)
+// dot: true to light up the decimal point
+func (d *Device) SetSegments(display int, position int, pattern byte, dot bool) {
+	d.setPattern(display, position, pattern, dot)
+	d.autoDisplay()
+}
 
-	digitPos := 0
-	runes := []rune(s) // runeのスライスに変換して、マルチバイト文字にも対応する
-	for i := 0; i < len(runes) && digitPos < MaxDigitsPerDisplay; i++ {
-		// Convert to uppercase to match the font map keys
-		char := runes[i]
-		if pattern, ok := font[char]; ok {
-			dot := false
-			// Look ahead for a dot
-			if i+1 < len(runes) && runes[i+1] == '.' {
-				dot = true
-				i++ // ドットを処理したので、次の文字はスキップ
-			}
-			d.setPattern(display, digitPos, pattern, dot)
-			digitPos++
-		} // If character is not in the font map, it's ignored.
-	}
+// SetSegmentsOverlay is like SetSegments, but adds the pattern to the
+// segments that are already lit.
+//
+// SetSegmentsOverlayはSetSegmentsと同様だが、既に点灯しているセグメントに
+// パターンを追加する。
+func (d *Device) SetSegmentsOverlay(display int, position int, pattern byte, dot bool) {
+	d.orPattern(display, position, pattern, dot)
+	d.autoDisplay()
 }
 
 // setPattern is a helper to directly set a segment pattern at a position.
 //
 // setPatternは、指定した位置にセグメントパターンを直接設定するためのヘルパー関数。
 func (d *Device) setPattern(display int, position int, pattern byte, dot bool) {
-	if display < 0 || display >= NumDisplays || position < 0 || position >= MaxDigitsPerDisplay {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits {
 		return
 	}
 
-	rowOffset := display * MaxDigitsPerDisplay
+	rowOffset := display * segmentRows
 	mask := ^byte(1 << position)
 
 	// Clear the bits for this digit position first
-	for i := 0; i < MaxDigitsPerDisplay; i++ {
+	for i := 0; i < segmentRows; i++ {
 		d.buffer[rowOffset+i] &= mask
 	}
 
+	d.orPattern(display, position, pattern, dot)
+}
+
+// orPattern is a helper to add a segment pattern to a position, keeping the
+// segments that are already lit.
+//
+// orPatternは、既に点灯しているセグメントを残したまま、指定した位置に
+// セグメントパターンを追加するためのヘルパー関数。
+func (d *Device) orPattern(display int, position int, pattern byte, dot bool) {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits {
+		return
+	}
+
+	rowOffset := display * segmentRows
+
 	// Set the new segment bits
 	for seg := 0; seg < 7; seg++ {
 		if (pattern>>seg)&1 == 1 {
@@ -327,12 +675,158 @@ func (d *Device) setPattern(display int, position int, pattern byte, dot bool) {
 	}
 }
 
+// digitPattern returns the segments lit at a position, with the decimal
+// point in bit 7.
+//
+// digitPatternは、指定した位置で点灯しているセグメントを返す。小数点は
+// ビット7になる。
+func (d *Device) digitPattern(display int, position int) byte {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits {
+		return 0
+	}
+	var pattern byte
+	for row := 0; row < segmentRows; row++ {
+		if d.buffer[display*segmentRows+row]&(1<<position) != 0 {
+			pattern |= 1 << row
+		}
+	}
+	return pattern
+}
+
 // Display transfers the buffer's content to the LED driver.
+// Displays disabled with EnableDisplay are sent as blank.
 //
 // Displayは、バッファの内容をLEDドライバに転送する。
+// EnableDisplayで無効にしたディスプレイは空白として送る。
 func (d *Device) Display() {
-	data := append([]byte{0x00}, d.buffer[:]...)
-	d.bus.Tx(uint16(d.Address), data, nil)
+	frame := d.frame()
+	data := append([]byte{0x00}, frame[:]...)
+	d.tx(data, nil)
+}
+
+// frame returns the buffer as it should appear on the chip, with the
+// disabled displays masked out and the blinks, the cursor and the flash
+// applied.
+//
+// frameは、無効なディスプレイを消し、点滅、カーソル、フラッシュを適用した、
+// チップに表示すべきバッファを返す。
+func (d *Device) frame() [16]byte {
+	frame := d.buffer
+	for display := 0; display < NumDisplays; display++ {
+		if (d.disabledDisplays|d.dutyOff)&(1<<display) == 0 {
+			continue
+		}
+		rowOffset := display * segmentRows
+		for i := 0; i < segmentRows; i++ {
+			frame[rowOffset+i] = 0
+		}
+	}
+	if d.separatorsOff {
+		for i := range frame {
+			frame[i] &^= d.separators[i]
+		}
+	}
+	if d.digitsOff {
+		for i := range frame {
+			frame[i] &^= d.blinkDigits[i/segmentRows]
+		}
+	}
+	if d.cursorShown {
+		d.applyCursor(&frame)
+	}
+	if d.flashOff {
+		digits := byte(1<<d.digits - 1)
+		for i := range frame {
+			if d.flashStyle == FlashInvert && d.disabledDisplays&(1<<(i/segmentRows)) == 0 {
+				frame[i] ^= digits
+			} else {
+				frame[i] = 0
+			}
+		}
+	}
+	if d.idleOff || d.presetOff {
+		frame = [16]byte{}
+	}
+	return frame
+}
+
+// EnableDisplay enables or disables one of the two displays.
+// A disabled display is blank on the LEDs, but its content stays in the
+// buffer and is shown again as soon as the display is enabled.
+//
+// EnableDisplayは、2つのディスプレイのいずれかを有効または無効にする。
+// 無効にしたディスプレイはLED上では消えるが、内容はバッファに残り、
+// 有効に戻すとすぐに再表示される。
+func (d *Device) EnableDisplay(display int, enabled bool) {
+	d.enableDisplay(display, enabled)
+	d.autoDisplay()
+}
+
+// enableDisplay is EnableDisplay without the auto flush.
+//
+// enableDisplayは、自動転送なしのEnableDisplay。
+func (d *Device) enableDisplay(display int, enabled bool) {
+	if display < 0 || display >= d.displays {
+		return
+	}
+	if enabled {
+		d.disabledDisplays &^= 1 << display
+	} else {
+		d.disabledDisplays |= 1 << display
+	}
+}
+
+// IsDisplayEnabled returns false if the display was disabled with EnableDisplay.
+//
+// IsDisplayEnabledは、EnableDisplayでディスプレイが無効にされていればfalseを返す。
+func (d *Device) IsDisplayEnabled(display int) bool {
+	if display < 0 || display >= d.displays {
+		return false
+	}
+	return d.disabledDisplays&(1<<display) == 0
+}
+
+// tx performs an I2C transaction with the chip.
+//
+// txは、チップとI2Cの通信を行う。
+func (d *Device) tx(w, r []byte) error {
+	err := d.bus.Tx(uint16(d.Address), w, r)
+	d.stats.Transactions++
+	if err != nil {
+		d.stats.Errors++
+		d.stats.LastError = err
+	}
+	return err
+}
+
+// GetBuffer returns a copy of the display RAM buffer.
+// Rows 0-7 hold segments a-g and dp of display A, rows 8-15 those of
+// display B, and bit n of each row is digit n.
+//
+// GetBufferは、表示用RAMバッファのコピーを返す。
+// 行0-7はディスプレイAのセグメントa-gとdp、行8-15はディスプレイBのもので、
+// 各行のビットnが桁nに対応する。
+func (d *Device) GetBuffer() [16]byte {
+	return d.buffer
+}
+
+// SetBuffer replaces the whole display RAM buffer, using the same layout as
+// GetBuffer. It is useful to show frames prepared in advance in one call.
+//
+// SetBufferは、表示用RAMバッファ全体をGetBufferと同じ形式で置き換える。
+// 事前に用意したフレームを一度に表示するのに便利。
+func (d *Device) SetBuffer(buffer [16]byte) {
+	d.buffer = buffer
+	d.autoDisplay()
+}
+
+// autoDisplay transfers the buffer if the Device was created WithAutoFlush.
+//
+// autoDisplayは、WithAutoFlushで作られたDeviceならバッファを転送する。
+func (d *Device) autoDisplay() {
+	if d.autoFlush {
+		d.Display()
+	}
 }
 
 // LightUpAll turns on all segments of all digits on both displays.
@@ -341,6 +835,12 @@ func (d *Device) Display() {
 // LightUpAllは、両方のディスプレイのすべての桁のすべてのセグメントを点灯させる。
 // これにより、ディスプレイが単純な光源として機能するようになる。
 func (d *Device) LightUpAll() {
+	d.lightUpAll()
+	d.autoDisplay()
+}
+
+// lightUpAll is LightUpAll without the auto flush.
+func (d *Device) lightUpAll() {
 	for i := range d.buffer {
 		d.buffer[i] = 0xFF // Turn on all 8 digits for this segment row
 	}
@@ -352,21 +852,45 @@ func (d *Device) LightUpAll() {
 // LightUpAllFadeBlockingは、フェードイン効果付きですべてのセグメントを点灯させる。
 // これはブロッキング関数。
 func (d *Device) LightUpAllFadeBlocking(delay time.Duration) {
-	d.LightUpAll()
+	d.lightUpAll()
 	for i := 0; i <= 15; i++ {
 		d.SetBrightness(uint8(i))
 		time.Sleep(delay)
 	}
 }
 
-// DisplayFadeBlocking is a blocking version of the fade effect.
+// StartLightUpAllFade is the non-blocking version of LightUpAllFadeBlocking:
+// it turns on all segments and fades the brightness up from 0 to 15. As a
+// fade, it is driven by Update (or UpdateFade) and reported by IsFading, so
+// the lamp can fade in while the application polls buttons. It is ignored
+// while another fade runs.
+//
+// StartLightUpAllFadeは、LightUpAllFadeBlockingのノンブロッキング版。
+// すべてのセグメントを点灯し、明るさを0から15まで上げる。フェードなので
+// Update(またはUpdateFade)で動き、IsFadingで分かるので、アプリケーションが
+// ボタンを読んでいる間にランプをフェードインできる。別のフェード中は無視
+// される。
+func (d *Device) StartLightUpAllFade(delay time.Duration) {
+	if d.IsFading() {
+		d.lastAnimation = d.fade
+		return
+	}
+	d.lightUpAll()
+	d.startFade(&fadeAnimation{delay: delayTicks(delay), high: MaxFineBrightness, to: MaxFineBrightness, in: true})
+}
+
+// DisplayFadeBlocking is a blocking version of the fade effect. Like
+// StartFade, it ends at the brightness from before the fade.
 // For non-blocking behavior, use StartFade() and UpdateFade() instead.
 //
-// DisplayFadeBlockingは、ブロッキング版のフェード効果。
+// DisplayFadeBlockingは、ブロッキング版のフェード効果。StartFadeと同様に、
+// フェード前の明るさで終わる。
 // ノンブロッキングで動かすには、代わりにStartFade()とUpdateFade()を使う。
 func (d *Device) DisplayFadeBlocking(delay time.Duration) {
+	brightness := d.currentBrightness
+
 	// Fade out
-	for i := int(d.currentBrightness); i >= 0; i-- {
+	for i := int(brightness); i >= 0; i-- {
 		d.SetBrightness(uint8(i))
 		time.Sleep(delay)
 	}
@@ -375,67 +899,317 @@ func (d *Device) DisplayFadeBlocking(delay time.Duration) {
 	d.Display()
 
 	// Fade in
-	for i := 0; i <= 15; i++ {
+	for i := 0; i <= int(brightness); i++ {
 		d.SetBrightness(uint8(i))
 		time.Sleep(delay)
 	}
 	// Ensure brightness is set to the final desired level
-	d.SetBrightness(15)
+	d.SetBrightness(brightness)
 }
 
-// StartFade initiates a non-blocking fade effect.
-// Call UpdateFade() repeatedly in your main loop to drive the animation.
+// StartFade initiates a non-blocking fade effect: the brightness goes down
+// to 0, the buffer is sent, and the brightness goes up again to where it
+// was before the fade.
+// Call Update (or UpdateFade) repeatedly in your main loop to drive the
+// animation. The brightness moves by one level per delay, which is rounded
+// down to milliseconds, in the quarter steps of SetBrightnessFine, so
+// Update should be called at least every few milliseconds.
 //
-// StartFadeは、ノンブロッキングのフェード効果を開始する。
-// アニメーションを動かすには、メインループでUpdate()を繰り返し呼び出す。
+// StartFadeは、ノンブロッキングのフェード効果を開始する。明るさを0まで下げ、
+// バッファを送り、再びフェード前の明るさまで上げる。
+// アニメーションを動かすには、メインループでUpdate(またはUpdateFade)を
+// 繰り返し呼び出す。明るさはdelay(ミリ秒単位に切り捨てる)ごとに1段階、
+// SetBrightnessFineの1/4段階ずつ動くので、Updateを少なくとも数ミリ秒ごとに
+// 呼び出す必要がある。
 func (d *Device) StartFade(delay time.Duration) {
-	if d.fadeState != fadeStateIdle {
+	d.startFade(&fadeAnimation{delay: delayTicks(delay), high: MaxFineBrightness, to: -1, out: true, in: true})
+}
+
+// StartFadeOut fades the brightness down to 0 without touching the
+// content, so that the application can compose the next screen, read
+// sensors and so on in the dark, then show it with StartFadeIn. It is
+// non-blocking: call Update from the main loop.
+//
+// StartFadeOutは、内容に触れずに明るさを0まで下げるので、アプリケーション
+// は暗い間に次の画面を組み立てたりセンサーを読んだりしてから、StartFadeInで
+// 表示できる。ノンブロッキングなので、メインループからUpdateを呼び出す。
+func (d *Device) StartFadeOut(delay time.Duration) {
+	d.startFade(&fadeAnimation{delay: delayTicks(delay), high: MaxFineBrightness, to: -1, out: true})
+}
+
+// StartFadeIn sends the buffer and fades the brightness up from 0 to where
+// it was before StartFadeOut, or to the current brightness if there was no
+// fade-out. It is non-blocking: call Update from the main loop.
+//
+// StartFadeInは、バッファを送り、明るさを0からStartFadeOutの前の明るさ
+// まで上げる。フェードアウトがなければ現在の明るさまで上げる。ノンブロッキ
+// ングなので、メインループからUpdateを呼び出す。
+func (d *Device) StartFadeIn(delay time.Duration) {
+	d.startFade(&fadeAnimation{delay: delayTicks(delay), high: MaxFineBrightness, to: -1, in: true})
+}
+
+// StartFadeRange is like StartFade, but the brightness only sweeps between
+// min and max, for example between 2 and 10 for a night mode: it goes down
+// from the current brightness (limited to the range) to min, the buffer is
+// sent, and it goes up to target (also limited to the range) instead of to
+// where it was before. min and max are limited to 0-15 and may be given in
+// either order.
+//
+// StartFadeRangeはStartFadeと同様だが、明るさはminとmaxの間だけを動く
+// (夜間モードなら2と10の間など)。明るさは現在の明るさ(範囲に制限する)から
+// minまで下がり、バッファを送り、フェード前の明るさの代わりにtarget(これも
+// 範囲に制限する)まで上がる。minとmaxは0-15に制限し、どちらの順で与えても
+// よい。
+func (d *Device) StartFadeRange(delay time.Duration, min, max, target uint8) {
+	min = uint8(clampBrightness(int(min), 0, 15))
+	max = uint8(clampBrightness(int(max), 0, 15))
+	if min > max {
+		min, max = max, min
+	}
+	d.startFade(&fadeAnimation{delay: delayTicks(delay), low: int(min) * 4, high: int(max) * 4, to: int(target) * 4, out: true, in: true})
+}
+
+// startFade starts fade unless a fade is already running.
+//
+// startFadeは、フェード中でなければfadeを開始する。
+func (d *Device) startFade(fade *fadeAnimation) {
+	if d.IsFading() {
+		d.lastAnimation = d.fade
 		return // Already fading
 	}
-	d.fadeDelay = delay
-	d.fadeState = fadeStateOut
-	d.fadeStep = int(d.currentBrightness)
-	d.lastUpdateTime = time.Now()
+	d.fade = fade
+	d.Animate(d.fade)
+}
+
+// clampBrightness limits a brightness level to low to high.
+//
+// clampBrightnessは、明るさのレベルをlowからhighまでに制限する。
+func clampBrightness(level, low, high int) int {
+	return min(max(level, low), high)
 }
 
 // UpdateFade drives the non-blocking fade animation.
 // It should be called frequently from the main application loop.
 // Returns true if the device is currently in a fade animation.
+// It is the same as Update, which also drives the other animations.
 //
 // UpdateFadeは、ノンブロッキングのフェードアニメーションを動かす。
 // アプリケーションのメインループから頻繁に呼び出す必要がある。
 // フェードアニメーション中はtrueを返す。
+// 他のアニメーションも動かすUpdateと同じ。
 func (d *Device) UpdateFade() bool {
-	if d.fadeState == fadeStateIdle || time.Since(d.lastUpdateTime) < d.fadeDelay {
-		return d.IsFading()
-	}
-
-	d.lastUpdateTime = time.Now()
+	d.Update()
+	return d.IsFading()
+}
 
-	switch d.fadeState {
-	case fadeStateOut:
-		d.SetBrightness(uint8(d.fadeStep))
-		d.fadeStep--
-		if d.fadeStep < 0 {
-			d.Display() // Switch content when fully faded out
-			d.fadeState = fadeStateIn
-			d.fadeStep = 0
+// UpdateFadeElapsed drives the non-blocking fade by dt, the time since the
+// last call, instead of reading the clock, so that it can be called from a
+// timer interrupt with a known period on targets where reading the time is
+// expensive. The fade takes as many steps as dt covers. It should be used
+// instead of Update or UpdateFade for the fade, not together with them.
+// Returns true if the device is still in a fade animation.
+//
+// UpdateFadeElapsedは、時計を読む代わりに、前回の呼び出しからの時間dtだけ
+// ノンブロッキングのフェードを動かすので、時刻の読み取りが重いターゲット
+// では、周期の分かっているタイマー割り込みから呼び出せる。フェードはdtに
+// 収まるだけステップを進める。フェードにはUpdateやUpdateFadeと一緒に
+// ではなく、その代わりに使う。フェード中はtrueを返す。
+func (d *Device) UpdateFadeElapsed(dt time.Duration) bool {
+	if !d.IsFading() {
+		return false
+	}
+	f := d.fade
+	f.elapsed += ticks(max(dt, 0) * 4)
+	for f.state != fadeStateIdle && f.elapsed >= f.delay {
+		f.elapsed -= f.delay
+		if f.advance(d) {
+			d.Display()
 		}
-	case fadeStateIn:
-		d.SetBrightness(uint8(d.fadeStep))
-		d.fadeStep++
-		if d.fadeStep > 15 {
-			d.fadeState = fadeStateIdle // Fade finished
+		if f.delay == 0 {
+			break
 		}
 	}
 	return d.IsFading()
 }
 
+// StopFade ends the non-blocking fade at once: the brightness goes to the
+// end of the fade (where it was before, or the target of StartFadeRange)
+// and the buffer is sent, as at the end of the fade. StartFade is ignored
+// while a fade runs, so stop it first to start over.
+//
+// StopFadeは、ノンブロッキングのフェードをすぐに終える。フェードの終わりと
+// 同様に、明るさをフェードの終わり(フェード前、またはStartFadeRangeの
+// target)にしてバッファを送る。フェード中のStartFadeは無視されるので、
+// やり直すにはまずこれで止める。
+func (d *Device) StopFade() {
+	if !d.IsFading() {
+		return
+	}
+	d.fade.Stop(d)
+	d.Display()
+}
+
 // IsFading returns true if the device is currently in a non-blocking fade animation.
 //
 // IsFadingは、デバイスがノンブロッキングのフェードアニメーション中であればtrueを返す。
 func (d *Device) IsFading() bool {
-	return d.fadeState != fadeStateIdle
+	return d.fade != nil && !d.fade.Done()
+}
+
+// fadeAnimation is the Animation started by StartFade. It moves the
+// brightness in the quarter steps of SetBrightnessFine, so that the fade is
+// smoother than the 16 levels of the chip. Its times are kept in ticks
+// rather than as time.Time and time.Duration, so that a fade takes little
+// RAM on bare-metal targets: the delay per level in milliseconds, and the
+// other times in quarter milliseconds, in which the same number is the
+// delay per quarter step. The ticks wrap around, which the subtraction in
+// Step handles.
+//
+// fadeAnimationは、StartFadeで始めるAnimation。明るさをSetBrightnessFineの
+// 1/4段階で動かすので、チップの16段階より滑らかにフェードする。時間は
+// time.Timeやtime.Durationではなくティックで保持するので、ベアメタルの
+// ターゲットでもフェードのRAMは少ない。1段階の遅延はミリ秒単位、その他の
+// 時間は1/4ミリ秒単位で、同じ数が1/4段階の遅延になる。ティックは一周するが、
+// Stepの引き算がそれを扱う。
+type fadeAnimation struct {
+	delay uint32
+	state fadeState
+	step  int
+	// low and high bound the brightness in quarter steps; the fade goes
+	// from from down to low and back up to to, which is the brightness
+	// before the fade if it is given as -1.
+	// lowとhighは1/4段階での明るさの範囲。フェードはfromからlowまで下がり、
+	// toまで戻る。toを-1で与えた場合はフェード前の明るさになる。
+	low, high int
+	from, to  int
+	// out and in choose the halves of the fade to run.
+	// outとinは、実行するフェードの半分を選ぶ。
+	out, in bool
+	last    uint32
+	// elapsed is the time given to UpdateFadeElapsed not yet used by a step.
+	// elapsedは、UpdateFadeElapsedに与えられ、まだステップに使っていない時間。
+	elapsed uint32
+}
+
+// Start begins fading out from the current brightness, or fading in if
+// there is no fade-out.
+func (f *fadeAnimation) Start(d *Device, now time.Time) {
+	fine := int(d.GetBrightnessFine())
+	if f.to < 0 {
+		f.to = fine
+		if !f.out && d.fadedOut {
+			f.to = int(d.fadeRestore)
+		}
+	}
+	f.from = clampBrightness(fine, f.low, f.high)
+	f.to = clampBrightness(f.to, f.low, f.high)
+	f.last = tickOf(now) * 4
+	switch {
+	case !f.in:
+		if !d.fadedOut {
+			d.fadedOut = true
+			d.fadeRestore = uint8(fine)
+		}
+	case !f.out:
+		d.fadedOut = false
+	}
+	f.state = fadeStateOut
+	f.step = f.from
+	if !f.out {
+		f.state = fadeStateIn
+		f.step = f.low
+		d.SetBrightnessFine(uint8(f.low))
+		d.Display()
+	}
+}
+
+// Step moves the brightness by one level per delay, a quarter step at a
+// time.
+func (f *fadeAnimation) Step(d *Device, now time.Time) bool {
+	tick := tickOf(now) * 4
+	if f.state == fadeStateIdle || tick-f.last < f.delay {
+		return false
+	}
+	// Keep the fractions of a tick, unless the steps have fallen behind.
+	if f.last += f.delay; tick-f.last >= f.delay {
+		f.last = tick
+	}
+	return f.advance(d)
+}
+
+// advance moves the brightness by a quarter step, returning true when the
+// content should be sent.
+//
+// advanceは、明るさを1/4段階動かし、内容を送るべきときにtrueを返す。
+func (f *fadeAnimation) advance(d *Device) bool {
+	switch f.state {
+	case fadeStateOut:
+		d.SetBrightnessFine(uint8(f.step))
+		f.step--
+		if f.step < f.low {
+			if !f.in {
+				f.state = fadeStateIdle // Faded out, the content stays
+				return false
+			}
+			f.state = fadeStateIn
+			f.step = f.low
+			return true // Switch content when fully faded out
+		}
+	case fadeStateIn:
+		d.SetBrightnessFine(uint8(f.step))
+		f.step++
+		if f.step > f.to {
+			f.state = fadeStateIdle // Fade finished
+		}
+	}
+	return false
+}
+
+// Done reports whether the fade has finished.
+func (f *fadeAnimation) Done() bool {
+	return f.state == fadeStateIdle
+}
+
+// Stop ends the fade at once at its target brightness, or at its lowest
+// for a fade-out.
+func (f *fadeAnimation) Stop(d *Device) {
+	if f.state != fadeStateIdle {
+		f.state = fadeStateIdle
+		if f.in {
+			d.SetBrightnessFine(uint8(f.to))
+		} else {
+			d.SetBrightnessFine(uint8(f.low))
+		}
+	}
+}
+
+// FadeProgress returns how far the non-blocking fade has progressed, from 0
+// to 100 percent. It returns 100 when no fade is running.
+//
+// FadeProgressは、ノンブロッキングのフェードの進み具合を0から100パーセント
+// で返す。フェード中でなければ100を返す。
+func (d *Device) FadeProgress() uint8 {
+	if !d.IsFading() {
+		return 100
+	}
+	// The fade steps from from down to low, then from low up to to.
+	f := d.fade
+	var outSteps, inSteps int
+	if f.out {
+		outSteps = f.from - f.low + 1
+	}
+	if f.in {
+		inSteps = f.to - f.low + 1
+	}
+	var done int
+	switch f.state {
+	case fadeStateOut:
+		done = f.from - f.step
+	case fadeStateIn:
+		done = outSteps + f.step - f.low
+	}
+	total := outSteps + inSteps
+	return uint8(done * 100 / total)
 }
 
 // SetBrightness sets the display brightness (0-15).
@@ -445,6 +1219,34 @@ func (d *Device) SetBrightness(brightness uint8) {
 	if brightness > 15 {
 		brightness = 15
 	}
+	d.ditherFraction = 0
+	d.sendBrightness(brightness)
+}
+
+// sendBrightness sends the brightness level (0-15) without ending the
+// dithering of SetBrightnessFine.
+//
+// sendBrightnessは、SetBrightnessFineのディザリングを終えずに明るさ(0-15)を
+// 送る。
+func (d *Device) sendBrightness(brightness uint8) {
 	d.currentBrightness = brightness
-	d.bus.Tx(uint16(d.Address), []byte{ht16k33SetBrightness | brightness}, nil)
+	d.ApplySetup()
+}
+
+// GetBrightness returns the brightness level (0-15) last sent to the chip,
+// including the levels set while fading.
+//
+// GetBrightnessは、最後にチップへ送った明るさ(0-15)を返す。
+// フェード中に設定された明るさも含む。
+func (d *Device) GetBrightness() uint8 {
+	return d.currentBrightness
+}
+
+// IsDisplayOn returns true if the display has been turned on by Configure
+// or DisplayOn, and not turned off by DisplayOff.
+//
+// IsDisplayOnは、ConfigureまたはDisplayOnでディスプレイがオンになり、
+// DisplayOffでオフになっていなければtrueを返す。
+func (d *Device) IsDisplayOn() bool {
+	return d.displayOn
 }
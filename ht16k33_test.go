@@ -4,6 +4,7 @@ package ht16k33
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -12,16 +13,56 @@ import (
 type mockI2C struct {
 	addr uint16
 	data []byte
+	// ram emulates the display RAM of the chip.
+	ram [16]byte
+	// keys emulates the key data RAM of the chip (0x40-0x45).
+	keys [6]byte
+	// intFlag emulates the INT flag register of the chip (0x60).
+	intFlag byte
+	// err, if set, is returned from every transaction.
+	err error
+	// ramWrites counts the writes of data to the display RAM.
+	ramWrites int
 }
 
 // Tx fakes the I2C transaction, recording the data that was supposed to be sent.
+// Writes starting with a display RAM address (0x00-0x0F) update ram, and
+// reads return ram from the given address. Reads from 0x40-0x45 return keys,
+// and from 0x60 intFlag.
 func (m *mockI2C) Tx(addr uint16, w, r []byte) error {
 	m.addr = addr
 	m.data = make([]byte, len(w))
 	copy(m.data, w)
+	if m.err != nil {
+		return m.err
+	}
+	if len(w) > 0 && w[0] < byte(len(m.ram)) {
+		if len(w) > 1 {
+			m.ramWrites++
+		}
+		copy(m.ram[w[0]:], w[1:])
+		copy(r, m.ram[w[0]:])
+	}
+	if len(w) > 0 && w[0] >= keyDataAddress && w[0] < keyDataAddress+byte(len(m.keys)) {
+		copy(r, m.keys[w[0]-keyDataAddress:])
+	}
+	if len(w) > 0 && w[0] == interruptFlagAddress && len(r) > 0 {
+		r[0] = m.intFlag
+	}
 	return nil
 }
 
+// newTestDevice creates a Device on the given mock bus at the default address,
+// with a fakeClock that only moves with advanceClock.
+func newTestDevice(t *testing.T, bus *mockI2C) *Device {
+	t.Helper()
+	device, err := New(bus, 0x70, WithClock(&fakeClock{now: testTime}))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	return device
+}
+
 // TestSetDigitOnDisplay verifies that setting a single digit correctly modifies the buffer.
 func TestSetDigitOnDisplay(t *testing.T) {
 	testCases := []struct {
@@ -71,7 +112,7 @@ func TestSetDigitOnDisplay(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockBus := &mockI2C{}
-			device := New(mockBus, 0x70) // Creates a device with a zeroed buffer
+			device := newTestDevice(t, mockBus) // Creates a device with a zeroed buffer
 
 			device.SetDigitOnDisplay(tc.display, tc.position, tc.char, tc.dot)
 
@@ -82,6 +123,33 @@ func TestSetDigitOnDisplay(t *testing.T) {
 	}
 }
 
+// TestSetSegments verifies that a raw pattern replaces the digit.
+func TestSetSegments(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetDigitOnDisplay(1, 2, '8', true)
+
+	// Top and bottom bars only, without the dot.
+	device.SetSegments(1, 2, segA|segD, false)
+
+	expectedBuffer := [16]byte{8: 1 << 2, 11: 1 << 2}
+	if !bytes.Equal(device.buffer[:], expectedBuffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedBuffer[:], device.buffer[:])
+	}
+}
+
+// TestOverlay verifies that overlay writes keep the existing segments.
+func TestOverlay(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetDigitOnDisplay(0, 1, '1', false)        // segments b and c
+	device.SetDigitOnDisplayOverlay(0, 1, '-', false) // adds segment g
+	device.SetSegmentsOverlay(0, 1, segA, true)       // adds segment a and the dot
+
+	expectedBuffer := [16]byte{0: 1 << 1, 1: 1 << 1, 2: 1 << 1, 6: 1 << 1, 7: 1 << 1}
+	if !bytes.Equal(device.buffer[:], expectedBuffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedBuffer[:], device.buffer[:])
+	}
+}
+
 // TestSetDigit16 verifies that setting a digit on the virtual 16-digit display works.
 func TestSetDigit16(t *testing.T) {
 	testCases := []struct {
@@ -112,7 +180,7 @@ func TestSetDigit16(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			mockBus := &mockI2C{}
-			device := New(mockBus, 0x70)
+			device := newTestDevice(t, mockBus)
 			device.SetDigit16(tc.position, tc.char, tc.dot)
 			if !bytes.Equal(device.buffer[:], tc.expectedBuffer[:]) {
 				t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", tc.expectedBuffer[:], device.buffer[:])
@@ -124,7 +192,7 @@ func TestSetDigit16(t *testing.T) {
 // TestWriteString verifies that writing a string correctly populates the buffer.
 func TestWriteString(t *testing.T) {
 	mockBus := &mockI2C{}
-	device := New(mockBus, 0x70)
+	device := newTestDevice(t, mockBus)
 
 	// Write "1." to display 0 and "2" to display 1
 	device.WriteString(0, "1.")
@@ -154,10 +222,135 @@ func TestWriteString(t *testing.T) {
 	}
 }
 
+// TestNew verifies that New validates its arguments.
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		name        string
+		bus         I2CBus
+		address     uint8
+		expectedErr error
+	}{
+		{name: "Lowest address", bus: &mockI2C{}, address: 0x70},
+		{name: "Highest address", bus: &mockI2C{}, address: 0x77},
+		{name: "Address too low", bus: &mockI2C{}, address: 0x6F, expectedErr: ErrInvalidAddress},
+		{name: "Address too high", bus: &mockI2C{}, address: 0x78, expectedErr: ErrInvalidAddress},
+		{name: "Nil bus", bus: nil, address: 0x70, expectedErr: ErrNilBus},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device, err := New(tc.bus, tc.address)
+			if err != tc.expectedErr {
+				t.Fatalf("FAIL: New() error is wrong!\nExpected: %v\nGot:      %v", tc.expectedErr, err)
+			}
+			if err == nil && device == nil {
+				t.Errorf("FAIL: New() returned a nil Device without an error")
+			}
+			if err != nil && device != nil {
+				t.Errorf("FAIL: New() returned a Device together with an error")
+			}
+		})
+	}
+}
+
+// TestConfigure verifies the Configure settings and the RAM verification.
+func TestConfigure(t *testing.T) {
+	t.Run("Brightness", func(t *testing.T) {
+		mockBus := &mockI2C{}
+		device := newTestDevice(t, mockBus)
+		if err := device.Configure(Config{Brightness: 3}); err != nil {
+			t.Fatalf("Configure() returned an unexpected error: %v", err)
+		}
+		expected := []byte{ht16k33SetBrightness | 3}
+		if !bytes.Equal(mockBus.data, expected) {
+			t.Errorf("FAIL: Last command is wrong!\nExpected: %x\nGot:      %x", expected, mockBus.data)
+		}
+	})
+
+	t.Run("ClearRAM and Verify", func(t *testing.T) {
+		mockBus := &mockI2C{}
+		mockBus.ram[4] = 0xFF // Garbage left from a previous run
+		device := newTestDevice(t, mockBus)
+		device.buffer[0] = 0xFF
+		if err := device.Configure(Config{ClearRAM: true, Verify: true}); err != nil {
+			t.Fatalf("Configure() returned an unexpected error: %v", err)
+		}
+		if mockBus.ram != [16]byte{} || device.buffer != [16]byte{} {
+			t.Errorf("FAIL: RAM or buffer was not cleared!\nRAM:    %x\nBuffer: %x", mockBus.ram[:], device.buffer[:])
+		}
+	})
+
+	t.Run("Verify fails without a chip", func(t *testing.T) {
+		device := newTestDevice(t, &mockI2C{})
+		device.bus = floatingI2C{}
+		if err := device.Configure(Config{Verify: true}); err != ErrVerifyFailed {
+			t.Errorf("FAIL: Configure() should return ErrVerifyFailed, got %v", err)
+		}
+	})
+
+	t.Run("Bus error", func(t *testing.T) {
+		busErr := errors.New("bus error")
+		device := newTestDevice(t, &mockI2C{err: busErr})
+		if err := device.Configure(Config{}); err != busErr {
+			t.Errorf("FAIL: Configure() should return the bus error, got %v", err)
+		}
+	})
+}
+
+// TestGetters verifies the brightness, display and fade state accessors.
+func TestGetters(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	if device.IsDisplayOn() {
+		t.Errorf("FAIL: Display should be off before Configure")
+	}
+	if err := device.Configure(Config{Brightness: 4}); err != nil {
+		t.Fatalf("Configure() returned an unexpected error: %v", err)
+	}
+	if !device.IsDisplayOn() {
+		t.Errorf("FAIL: Display should be on after Configure")
+	}
+	if got := device.GetBrightness(); got != 4 {
+		t.Errorf("FAIL: GetBrightness() = %d, expected 4", got)
+	}
+
+	if got := device.FadeProgress(); got != 100 {
+		t.Errorf("FAIL: FadeProgress() = %d before fading, expected 100", got)
+	}
+	device.StartFade(0)
+	if got := device.FadeProgress(); got != 0 {
+		t.Errorf("FAIL: FadeProgress() = %d at the start, expected 0", got)
+	}
+	last := uint8(0)
+	for device.UpdateFade() {
+		got := device.FadeProgress()
+		if got < last || got >= 100 {
+			t.Fatalf("FAIL: FadeProgress() = %d after %d, expected to increase below 100", got, last)
+		}
+		last = got
+	}
+	if got := device.FadeProgress(); got != 100 {
+		t.Errorf("FAIL: FadeProgress() = %d after fading, expected 100", got)
+	}
+	if got := device.GetBrightness(); got != 4 {
+		t.Errorf("FAIL: GetBrightness() = %d after fading, expected 4", got)
+	}
+}
+
+// floatingI2C pretends to be a bus without a chip, where every read
+// returns 0xFF from the pull-up resistors.
+type floatingI2C struct{}
+
+func (floatingI2C) Tx(addr uint16, w, r []byte) error {
+	for i := range r {
+		r[i] = 0xFF
+	}
+	return nil
+}
+
 // TestDisplay verifies that the Display method sends the correct data over I2C.
 func TestDisplay(t *testing.T) {
 	mockBus := &mockI2C{}
-	device := New(mockBus, 0x70)
+	device := newTestDevice(t, mockBus)
 
 	// Set some data in the buffer to test with
 	device.buffer[0] = 0xAA
@@ -173,10 +366,52 @@ func TestDisplay(t *testing.T) {
 	}
 }
 
+// TestEnableDisplay verifies that a disabled display is masked on flush only.
+func TestEnableDisplay(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.LightUpAll()
+
+	device.EnableDisplay(1, false)
+	device.Display()
+
+	expectedRAM := [16]byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	if mockBus.ram != expectedRAM {
+		t.Errorf("FAIL: RAM with display 1 disabled is wrong!\nExpected: %x\nGot:      %x", expectedRAM[:], mockBus.ram[:])
+	}
+	if device.buffer[15] != 0xFF {
+		t.Errorf("FAIL: Disabling a display must not change the buffer")
+	}
+
+	device.EnableDisplay(1, true)
+	device.Display()
+	if mockBus.ram != device.buffer {
+		t.Errorf("FAIL: RAM after enabling display 1 is wrong!\nExpected: %x\nGot:      %x", device.buffer[:], mockBus.ram[:])
+	}
+}
+
+// TestGetSetBuffer verifies bulk access to the buffer.
+func TestGetSetBuffer(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	frame := [16]byte{0x01, 0x02, 0x03, 15: 0x80}
+
+	device.SetBuffer(frame)
+	got := device.GetBuffer()
+	if got != frame {
+		t.Errorf("FAIL: GetBuffer() after SetBuffer() is wrong!\nExpected: %x\nGot:      %x", frame[:], got[:])
+	}
+
+	// The returned buffer is a copy.
+	got[0] = 0xFF
+	if device.buffer[0] != 0x01 {
+		t.Errorf("FAIL: Changing the result of GetBuffer() must not change the Device")
+	}
+}
+
 // TestClearOnDisplay verifies that a single display can be cleared.
 func TestClearOnDisplay(t *testing.T) {
 	mockBus := &mockI2C{}
-	device := New(mockBus, 0x70)
+	device := newTestDevice(t, mockBus)
 
 	// Write something to both displays first
 	device.WriteString(0, "88")
@@ -187,7 +422,7 @@ func TestClearOnDisplay(t *testing.T) {
 
 	// To get the expected state, create a new device and only write to display 1.
 	// This is clearer than calculating the expected buffer manually.
-	expectedDevice := New(mockBus, 0x70)
+	expectedDevice := newTestDevice(t, mockBus)
 	expectedDevice.WriteString(1, "99")
 	expectedBuffer := expectedDevice.buffer
 
@@ -199,7 +434,7 @@ func TestClearOnDisplay(t *testing.T) {
 // TestClearAll verifies that both displays can be cleared.
 func TestClearAll(t *testing.T) {
 	mockBus := &mockI2C{}
-	device := New(mockBus, 0x70)
+	device := newTestDevice(t, mockBus)
 
 	device.WriteString(0, "123")
 	device.WriteString(1, "456")
@@ -214,7 +449,7 @@ func TestClearAll(t *testing.T) {
 // TestLightUpAll verifies that all segments are turned on.
 func TestLightUpAll(t *testing.T) {
 	mockBus := &mockI2C{}
-	device := New(mockBus, 0x70)
+	device := newTestDevice(t, mockBus)
 
 	device.LightUpAll()
 
@@ -249,8 +484,15 @@ func ExampleDevice_WriteString() {
 
 	// Create and configure the display driver.
 	// ディスプレイドライバを作成して設定する。
-	display := New(mockBus, 0x70)
-	display.Configure()
+	display, err := New(mockBus, 0x70)
+	if err != nil {
+		fmt.Println("could not create display:", err)
+		return
+	}
+	if err := display.Configure(Config{}); err != nil {
+		fmt.Println("could not configure display:", err)
+		return
+	}
 
 	// Write different strings to each 8-digit display.
 	// それぞれの8桁ディスプレイに、違う文字列を書き込む。
@@ -0,0 +1,87 @@
+package ht16k33
+
+import "time"
+
+// IdleAction decides what the screensaver of SetIdleTimeout does.
+//
+// IdleActionは、SetIdleTimeoutのスクリーンセーバーが何をするかを決める。
+type IdleAction uint8
+
+const (
+	// IdleDim sets the lowest brightness (default).
+	IdleDim IdleAction = iota
+	// IdleBlank blanks the displays, keeping their content.
+	IdleBlank
+)
+
+// SetIdleTimeout turns on a screensaver that dims or blanks the displays
+// when NotifyActivity has not been called for timeout, to save power and
+// LED lifetime on rarely viewed panels. The next NotifyActivity restores
+// them. 0 turns the screensaver off. It is non-blocking: call Update (or
+// UpdateIdle) from the main loop.
+//
+// SetIdleTimeoutは、NotifyActivityがtimeoutの間呼び出されないとディスプレイ
+// を暗くするか消すスクリーンセーバーを有効にし、あまり見ないパネルの電力と
+// LEDの寿命を節約する。次のNotifyActivityで元に戻す。0ならスクリーン
+// セーバーを無効にする。ノンブロッキングなので、メインループからUpdate
+// (またはUpdateIdle)を呼び出す。
+func (d *Device) SetIdleTimeout(timeout time.Duration, action IdleAction) {
+	d.wake()
+	d.idleTimeout = delayTicks(timeout)
+	d.idleAction = action
+	d.lastActivity = d.tick()
+}
+
+// NotifyActivity tells the screensaver that the user is active, for example
+// when a button is pressed, restoring the displays if it is on.
+//
+// NotifyActivityは、たとえばボタンが押されたときに、ユーザーが操作している
+// ことをスクリーンセーバーに伝え、有効ならディスプレイを元に戻す。
+func (d *Device) NotifyActivity() {
+	d.lastActivity = d.tick()
+	d.wake()
+}
+
+// IsIdle returns true while the screensaver dims or blanks the displays.
+//
+// IsIdleは、スクリーンセーバーがディスプレイを暗くするか消している間に
+// trueを返す。
+func (d *Device) IsIdle() bool {
+	return d.idle
+}
+
+// UpdateIdle turns the screensaver on when the timeout has passed since the
+// last activity. It returns true when it has turned it on.
+//
+// UpdateIdleは、最後の操作からタイムアウトが過ぎたときにスクリーンセーバー
+// を有効にする。有効にしたときはtrueを返す。
+func (d *Device) UpdateIdle() bool {
+	if d.idleTimeout == 0 || d.idle || d.tick()-d.lastActivity < d.idleTimeout {
+		return false
+	}
+	d.idle = true
+	if d.idleAction == IdleBlank {
+		d.idleOff = true
+		d.Display()
+	} else {
+		d.idleRestore = d.currentBrightness
+		d.SetBrightness(0)
+	}
+	return true
+}
+
+// wake turns the screensaver off if it is on.
+//
+// wakeは、スクリーンセーバーが有効なら無効にする。
+func (d *Device) wake() {
+	if !d.idle {
+		return
+	}
+	d.idle = false
+	if d.idleOff {
+		d.idleOff = false
+		d.Display()
+	} else {
+		d.SetBrightness(d.idleRestore)
+	}
+}
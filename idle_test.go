@@ -0,0 +1,54 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIdleDim verifies that the brightness goes down after the timeout and back on activity.
+func TestIdleDim(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetBrightness(8)
+	device.SetIdleTimeout(time.Minute, IdleDim)
+
+	device.Update()
+	if device.IsIdle() || device.GetBrightness() != 8 {
+		t.Fatalf("FAIL: Screensaver should wait for the timeout")
+	}
+	advanceClock(device, time.Hour)
+	if !device.UpdateIdle() || !device.IsIdle() || device.GetBrightness() != 0 {
+		t.Fatalf("FAIL: Screensaver should dim after the timeout, got %d", device.GetBrightness())
+	}
+	device.NotifyActivity()
+	if device.IsIdle() || device.GetBrightness() != 8 {
+		t.Errorf("FAIL: NotifyActivity() should restore the brightness!\nExpected: %d\nGot:      %d", 8, device.GetBrightness())
+	}
+}
+
+// TestIdleBlank verifies that the displays are blanked and their content is sent again on activity.
+func TestIdleBlank(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "12")
+	device.Display()
+	device.SetIdleTimeout(time.Minute, IdleBlank)
+
+	advanceClock(device, time.Hour)
+	device.Update()
+	if !device.IsIdle() || mockBus.ram != [16]byte{} {
+		t.Fatalf("FAIL: Screensaver should blank the displays, got %x", mockBus.ram)
+	}
+	device.NotifyActivity()
+	if device.IsIdle() || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: NotifyActivity() should send the content again!\nExpected: %x\nGot:      %x", device.buffer, mockBus.ram)
+	}
+
+	// Turning the screensaver off while idle restores the displays too.
+	advanceClock(device, time.Hour)
+	device.Update()
+	device.SetIdleTimeout(0, IdleBlank)
+	advanceClock(device, time.Hour)
+	if device.Update(); device.IsIdle() || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: SetIdleTimeout(0) should turn the screensaver off")
+	}
+}
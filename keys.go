@@ -0,0 +1,297 @@
+package ht16k33
+
+import "time"
+
+// KeyColumns is the number of key scan lines (KS0-KS2) of the HT16K33, and
+// KeyRows the number of keys (K1-K13) on each of them.
+const (
+	KeyColumns = 3
+	KeyRows    = 13
+)
+
+// Keys is the raw key bitmap read by ReadKeys: element n is scan line KSn,
+// and bit m of it is set while key K(m+1) on that line is pressed.
+//
+// Keysは、ReadKeysで読む生のキーのビットマップ。要素nはスキャンラインKSnで、
+// そのラインのキーK(m+1)が押されている間はビットmが立つ。
+type Keys [KeyColumns]uint16
+
+// Pressed returns true if the key at row (0-12, for K1-K13) on scan line
+// column (0-2, for KS0-KS2) is pressed.
+//
+// Pressedは、スキャンラインcolumn(0-2、KS0-KS2)のrow(0-12、K1-K13)のキーが
+// 押されていればtrueを返す。
+func (k Keys) Pressed(row, column int) bool {
+	if row < 0 || row >= KeyRows || column < 0 || column >= KeyColumns {
+		return false
+	}
+	return k[column]&(1<<row) != 0
+}
+
+// ReadKeys reads the key data RAM (0x40-0x45) of the chip's 13x3 key matrix
+// scanner, so that buttons wired to the same chip can be used. The keys
+// K1-K13 share their pins with the ROW lines that drive the segments, so
+// the buttons need diodes as in the HT16K33 datasheet.
+//
+// ReadKeysは、チップの13x3のキーマトリクススキャナーのキーデータRAM
+// (0x40-0x45)を読むので、同じチップにつないだボタンを使える。キーK1-K13は
+// セグメントを駆動するROWラインとピンを共有するので、ボタンにはHT16K33の
+// データシートのとおりダイオードが必要。
+func (d *Device) ReadKeys() (Keys, error) {
+	var data [2 * KeyColumns]byte
+	if err := d.tx([]byte{keyDataAddress}, data[:]); err != nil {
+		return Keys{}, err
+	}
+	var keys Keys
+	for i := range keys {
+		keys[i] = (uint16(data[2*i]) | uint16(data[2*i+1])<<8) & (1<<KeyRows - 1)
+	}
+	return keys, nil
+}
+
+// ReadInterruptFlag reads the INT flag register (0x60), which the chip sets
+// while a key is pressed after a scan. It is a single byte, so it is a
+// cheap check before reading the whole key data RAM.
+//
+// ReadInterruptFlagは、INTフラグレジスタ(0x60)を読む。チップはスキャンの
+// 後にキーが押されている間これを設定する。1バイトだけなので、キーデータ
+// RAM全体を読む前の軽い確認になる。
+func (d *Device) ReadInterruptFlag() (bool, error) {
+	var flag [1]byte
+	if err := d.tx([]byte{interruptFlagAddress}, flag[:]); err != nil {
+		return false, err
+	}
+	return flag[0] != 0, nil
+}
+
+// InterruptMode is the use of the ROW15/INT pin, set with SetInterruptMode.
+//
+// InterruptModeは、SetInterruptModeで設定するROW15/INTピンの使い方。
+type InterruptMode uint8
+
+const (
+	// InterruptOff drives ROW15 as a segment row (default).
+	InterruptOff InterruptMode = iota
+	// InterruptActiveLow makes the pin an interrupt output that goes low
+	// while a key is pressed.
+	InterruptActiveLow
+	// InterruptActiveHigh makes the pin an interrupt output that goes high
+	// while a key is pressed.
+	InterruptActiveHigh
+)
+
+// SetInterruptMode configures the ROW15/INT pin as a segment row or as an
+// interrupt output for the key scanner, so that boards that wire the INT
+// pin can use it. ROW15 drives the decimal points of display B, which stay
+// dark while the pin is an interrupt output.
+//
+// SetInterruptModeは、ROW15/INTピンをセグメントの行か、キースキャナーの
+// 割り込み出力に設定するので、INTピンを配線したボードで使える。ROW15は
+// ディスプレイBの小数点を駆動するので、割り込み出力の間は小数点が消える。
+func (d *Device) SetInterruptMode(mode InterruptMode) {
+	d.interruptMode = mode
+	d.ApplySetup()
+}
+
+const (
+	// defaultKeyDebounce is how long a key change has to last until
+	// SetKeyTiming.
+	defaultKeyDebounce = 20 * time.Millisecond
+	// defaultKeyHoldDelay is how long a key is pressed before KeyHold.
+	defaultKeyHoldDelay = time.Second
+	// defaultKeyRepeatDelay is the time between KeyRepeat events.
+	defaultKeyRepeatDelay = 200 * time.Millisecond
+)
+
+// KeyEventType tells what happened to a key in a KeyEvent.
+//
+// KeyEventTypeは、KeyEventでキーに何が起きたかを表す。
+type KeyEventType uint8
+
+const (
+	// KeyPress is sent when a key is pressed.
+	KeyPress KeyEventType = iota
+	// KeyRelease is sent when a key is released.
+	KeyRelease
+	// KeyHold is sent once when a key has been pressed for the hold time.
+	KeyHold
+	// KeyRepeat is sent repeatedly after KeyHold while the key stays
+	// pressed.
+	KeyRepeat
+)
+
+// KeyEvent is an event returned by PollKeys.
+//
+// KeyEventは、PollKeysが返すイベント。
+type KeyEvent struct {
+	// Name is the name given with SetKeyName, or "" for an unnamed key.
+	// Nameは、SetKeyNameで付けた名前。名前のないキーなら""。
+	Name string
+	// Row (0-12, for K1-K13) and Column (0-2, for KS0-KS2) locate the key.
+	// Row(0-12、K1-K13)とColumn(0-2、KS0-KS2)はキーの位置を表す。
+	Row, Column int
+	Type        KeyEventType
+}
+
+// keyName is a name given with SetKeyName.
+type keyName struct {
+	row, column int
+	name        string
+}
+
+// keyHold follows a pressed key for KeyHold and KeyRepeat.
+type keyHold struct {
+	row, column int
+	next        uint32
+	held        bool
+}
+
+// SetKeyName gives the key at row (0-12, for K1-K13) on scan line column
+// (0-2, for KS0-KS2) a name for the events of PollKeys, so that the
+// application can refer to its buttons as "up", "set" and so on.
+//
+// SetKeyNameは、スキャンラインcolumn(0-2、KS0-KS2)のrow(0-12、K1-K13)の
+// キーに、PollKeysのイベントで使う名前を付けるので、アプリケーションは
+// ボタンを"up"や"set"などで参照できる。
+func (d *Device) SetKeyName(name string, row, column int) error {
+	if row < 0 || row >= KeyRows || column < 0 || column >= KeyColumns {
+		return ErrInvalidKey
+	}
+	for i := range d.keyNames {
+		if d.keyNames[i].row == row && d.keyNames[i].column == column {
+			d.keyNames[i].name = name
+			return nil
+		}
+	}
+	d.keyNames = append(d.keyNames, keyName{row, column, name})
+	return nil
+}
+
+// SetKeyTiming sets how long a key change has to last to be accepted, how
+// long a key is pressed before KeyHold, and the time between the KeyRepeat
+// events after it. 0 keeps the default of 20ms, 1s and 200ms.
+//
+// SetKeyTimingは、キーの変化を受け付けるまでに続く必要のある時間、KeyHold
+// までキーを押す時間、その後のKeyRepeatイベントの間隔を設定する。0なら
+// デフォルトの20ms、1秒、200msのままになる。
+func (d *Device) SetKeyTiming(debounce, hold, repeat time.Duration) {
+	d.keyDebounce = delayTicks(debounce)
+	d.keyHoldDelay = delayTicks(hold)
+	d.keyRepeatDelay = delayTicks(repeat)
+}
+
+// PollKeys reads the keys and returns the events since the last call: a
+// change is reported once it has lasted for the debounce time, and a key
+// pressed for the hold time sends KeyHold and then KeyRepeat. It should be
+// called frequently from the main loop. The returned slice is reused by the
+// next call.
+//
+// PollKeysはキーを読み、前回の呼び出しからのイベントを返す。変化はデバウンス
+// 時間続いたときに報告し、ホールド時間押されたキーはKeyHold、続いて
+// KeyRepeatを送る。メインループから頻繁に呼び出す必要がある。返すスライスは
+// 次の呼び出しで再利用する。
+func (d *Device) PollKeys() ([]KeyEvent, error) {
+	d.keyEvents = d.keyEvents[:0]
+	now := d.tick()
+	// Without an interrupt, the keys are only read again to debounce.
+	if !d.keyInterruptDriven || d.keyPending.Swap(false) || d.keyCandidate != d.keyStable {
+		raw, err := d.ReadKeys()
+		if err != nil {
+			return d.keyEvents, err
+		}
+		if raw != d.keyCandidate {
+			d.keyCandidate = raw
+			d.keyChanged = now
+		}
+	}
+	if d.keyCandidate != d.keyStable && now-d.keyChanged >= tickInterval(d.keyDebounce, defaultKeyDebounce) {
+		d.keysChanged(d.keyStable, d.keyCandidate, now)
+		d.keyStable = d.keyCandidate
+	}
+	holds := d.keyHolds[:0]
+	for _, h := range d.keyHolds {
+		if !d.keyStable.Pressed(h.row, h.column) {
+			continue
+		}
+		// The difference, unlike a comparison, survives the ticks wrapping.
+		if int32(now-h.next) >= 0 {
+			event := KeyRepeat
+			if !h.held {
+				event = KeyHold
+				h.held = true
+			}
+			h.next = now + tickInterval(d.keyRepeatDelay, defaultKeyRepeatDelay)
+			d.addKeyEvent(h.row, h.column, event)
+		}
+		holds = append(holds, h)
+	}
+	d.keyHolds = holds
+	return d.keyEvents, nil
+}
+
+// SetKeyInterruptDriven makes PollKeys read the keys over I2C only after
+// KeyInterrupt, and while a change is being debounced, instead of on every
+// call. It is meant for boards that wire the INT pin (see SetInterruptMode)
+// to a GPIO whose interrupt calls KeyInterrupt on both edges, for example:
+//
+//	pin.Configure(machine.PinConfig{Mode: machine.PinInputPullup})
+//	pin.SetInterrupt(machine.PinToggle, func(machine.Pin) {
+//		display.KeyInterrupt()
+//	})
+//
+// SetKeyInterruptDrivenは、PollKeysが毎回ではなく、KeyInterruptの後と
+// 変化のデバウンス中だけI2Cでキーを読むようにする。INTピン
+// (SetInterruptModeを参照)をGPIOにつなぎ、その両エッジの割り込みで
+// KeyInterruptを呼び出すボードのためのもの(例は上記)。
+func (d *Device) SetKeyInterruptDriven(enabled bool) {
+	d.keyInterruptDriven = enabled
+	d.keyPending.Store(true)
+}
+
+// KeyInterrupt tells the key subsystem that the chip has signalled a key
+// change on its INT pin. It only sets a flag, so it is safe to call from an
+// interrupt handler; the keys are read by the next PollKeys.
+//
+// KeyInterruptは、チップがINTピンでキーの変化を知らせたことをキーの
+// サブシステムに伝える。フラグを設定するだけなので、割り込みハンドラから
+// 安全に呼び出せる。キーは次のPollKeysで読む。
+func (d *Device) KeyInterrupt() {
+	d.keyPending.Store(true)
+}
+
+// keysChanged adds the KeyPress and KeyRelease events from old to keys, and
+// starts following the pressed keys for KeyHold.
+//
+// keysChangedは、oldからkeysへのKeyPressとKeyReleaseのイベントを追加し、
+// 押されたキーのKeyHoldのための追跡を始める。
+func (d *Device) keysChanged(old, keys Keys, now uint32) {
+	for column := range keys {
+		changed := old[column] ^ keys[column]
+		for row := 0; row < KeyRows; row++ {
+			if changed&(1<<row) == 0 {
+				continue
+			}
+			if keys.Pressed(row, column) {
+				d.addKeyEvent(row, column, KeyPress)
+				hold := tickInterval(d.keyHoldDelay, defaultKeyHoldDelay)
+				d.keyHolds = append(d.keyHolds, keyHold{row: row, column: column, next: now + hold})
+			} else {
+				d.addKeyEvent(row, column, KeyRelease)
+			}
+		}
+	}
+}
+
+// addKeyEvent adds an event for the key with its name.
+//
+// addKeyEventは、キーのイベントを名前付きで追加する。
+func (d *Device) addKeyEvent(row, column int, event KeyEventType) {
+	name := ""
+	for _, k := range d.keyNames {
+		if k.row == row && k.column == column {
+			name = k.name
+			break
+		}
+	}
+	d.keyEvents = append(d.keyEvents, KeyEvent{Name: name, Row: row, Column: column, Type: event})
+}
@@ -0,0 +1,157 @@
+package ht16k33
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestReadKeys verifies that the key data RAM is read into one bitmap per scan line.
+func TestReadKeys(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	// K1 and K13 on KS0, K9 on KS2, and unused bits that should be dropped.
+	mockBus.keys = [6]byte{0x01, 0xF0, 0x00, 0x00, 0x00, 0x01}
+
+	keys, err := device.ReadKeys()
+	if err != nil {
+		t.Fatalf("ReadKeys() returned an unexpected error: %v", err)
+	}
+	expected := Keys{0x1001, 0x0000, 0x0100}
+	if keys != expected {
+		t.Errorf("FAIL: Keys are wrong!\nExpected: %x\nGot:      %x", expected, keys)
+	}
+	if !keys.Pressed(12, 0) || !keys.Pressed(8, 2) || keys.Pressed(8, 1) || keys.Pressed(13, 0) {
+		t.Errorf("FAIL: Pressed() does not match the keys %x", keys)
+	}
+
+	mockBus.err = errors.New("bus error")
+	if _, err := device.ReadKeys(); err == nil {
+		t.Errorf("FAIL: ReadKeys() should return the bus error")
+	}
+}
+
+// TestPollKeys verifies the debouncing and the Press, Hold, Repeat and Release events of a named key.
+func TestPollKeys(t *testing.T) {
+	mockBus := &mockI2C{}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	device, err := New(mockBus, 0x70, WithClock(clock))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	if err := device.SetKeyName("set", 2, 1); err != nil {
+		t.Fatalf("SetKeyName() returned an unexpected error: %v", err)
+	}
+	if err := device.SetKeyName("bad", 13, 0); err != ErrInvalidKey {
+		t.Errorf("FAIL: SetKeyName() for K14 should return ErrInvalidKey, got %v", err)
+	}
+
+	poll := func(after time.Duration) []KeyEvent {
+		clock.advance(after)
+		events, err := device.PollKeys()
+		if err != nil {
+			t.Fatalf("PollKeys() returned an unexpected error: %v", err)
+		}
+		return events
+	}
+
+	// A bounce shorter than the debounce time is ignored.
+	mockBus.keys[2] = 0x04
+	poll(0)
+	mockBus.keys[2] = 0x00
+	if events := poll(10 * time.Millisecond); len(events) != 0 {
+		t.Errorf("FAIL: A bounce should not send events, got %v", events)
+	}
+
+	mockBus.keys[2] = 0x04
+	poll(0)
+	events := poll(20 * time.Millisecond)
+	expected := KeyEvent{Name: "set", Row: 2, Column: 1, Type: KeyPress}
+	if len(events) != 1 || events[0] != expected {
+		t.Fatalf("FAIL: Press event is wrong!\nExpected: %v\nGot:      %v", expected, events)
+	}
+
+	expectedTypes := []KeyEventType{KeyHold, KeyRepeat, KeyRepeat}
+	for i, typ := range expectedTypes {
+		after := time.Second
+		if i > 0 {
+			after = 200 * time.Millisecond
+		}
+		if events := poll(after); len(events) != 1 || events[0].Type != typ {
+			t.Errorf("FAIL: Event %d is wrong!\nExpected: %v\nGot:      %v", i, typ, events)
+		}
+	}
+
+	mockBus.keys[2] = 0x00
+	poll(0)
+	events = poll(20 * time.Millisecond)
+	if len(events) != 1 || events[0].Type != KeyRelease || events[0].Name != "set" {
+		t.Errorf("FAIL: Release event is wrong, got %v", events)
+	}
+	if events := poll(time.Second); len(events) != 0 {
+		t.Errorf("FAIL: A released key should not repeat, got %v", events)
+	}
+}
+
+// TestReadInterruptFlag verifies that any non-zero flag register reads as set.
+func TestReadInterruptFlag(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	for _, tc := range []struct {
+		register byte
+		expected bool
+	}{{0x00, false}, {0xFF, true}, {0x01, true}} {
+		mockBus.intFlag = tc.register
+		flag, err := device.ReadInterruptFlag()
+		if err != nil || flag != tc.expected {
+			t.Errorf("FAIL: ReadInterruptFlag() for %x is wrong!\nExpected: %v\nGot:      %v (%v)", tc.register, tc.expected, flag, err)
+		}
+	}
+}
+
+// TestSetInterruptMode verifies the ROW/INT set command sent for each mode.
+func TestSetInterruptMode(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.Configure(Config{})
+	for _, tc := range []struct {
+		mode     InterruptMode
+		expected byte
+	}{{InterruptActiveLow, 0xA1}, {InterruptActiveHigh, 0xA3}, {InterruptOff, 0xA0}} {
+		device.SetInterruptMode(tc.mode)
+		if len(mockBus.data) != 1 || mockBus.data[0] != tc.expected {
+			t.Errorf("FAIL: ROW/INT set is wrong!\nExpected: %x\nGot:      %x", tc.expected, mockBus.data)
+		}
+	}
+}
+
+// TestKeyInterruptDriven verifies that the keys are read only after KeyInterrupt and while debouncing.
+func TestKeyInterruptDriven(t *testing.T) {
+	mockBus := &mockI2C{}
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	device, err := New(mockBus, 0x70, WithClock(clock))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	device.SetKeyInterruptDriven(true)
+	device.PollKeys()
+
+	transactions := device.Stats().Transactions
+	mockBus.keys[0] = 0x01
+	if events, _ := device.PollKeys(); len(events) != 0 || device.Stats().Transactions != transactions {
+		t.Fatalf("FAIL: PollKeys() should not read without an interrupt")
+	}
+
+	device.KeyInterrupt()
+	device.PollKeys()
+	clock.advance(20 * time.Millisecond)
+	events, _ := device.PollKeys()
+	if len(events) != 1 || events[0].Type != KeyPress {
+		t.Fatalf("FAIL: PollKeys() should debounce after the interrupt, got %v", events)
+	}
+	transactions = device.Stats().Transactions
+	device.PollKeys()
+	if device.Stats().Transactions != transactions {
+		t.Errorf("FAIL: PollKeys() should stop reading once the keys are stable")
+	}
+}
@@ -0,0 +1,184 @@
+package ht16k33
+
+import "time"
+
+// MessageEffect is how a Message is shown.
+//
+// MessageEffectは、Messageの表示方法。
+type MessageEffect uint8
+
+const (
+	// EffectStatic shows the message as WriteString does.
+	EffectStatic MessageEffect = iota
+	// EffectScroll scrolls the message as StartScroll does.
+	EffectScroll
+	// EffectFlash shows the message and blanks the display in turn.
+	EffectFlash
+)
+
+// Default intervals of the message effects.
+const (
+	defaultScrollInterval = 250 * time.Millisecond
+	defaultFlashInterval  = 500 * time.Millisecond
+)
+
+// Message is one entry of a MessageQueue.
+//
+// Messageは、MessageQueueの1つの項目。
+type Message struct {
+	Text   string
+	Effect MessageEffect
+	// Duration is how long the message is shown. For EffectScroll, 0 means
+	// one pass of the text.
+	// Durationは、メッセージを表示する時間。EffectScrollでは、0ならテキストを
+	// 1回通す時間になる。
+	Duration time.Duration
+	// Interval is the scroll step or the flash period; 0 means 250ms for
+	// scrolling and 500ms for flashing.
+	// Intervalは、スクロールの1歩または点滅の周期。0ならスクロールは250ms、
+	// 点滅は500msになる。
+	Interval time.Duration
+}
+
+// MessageQueue plays messages one after another on a display, so that an
+// application can just enqueue notifications. It is non-blocking: call
+// Update from the main loop, like UpdateFade.
+//
+// MessageQueueは、ディスプレイでメッセージを順番に再生するので、アプリケー
+// ションは通知をキューに入れるだけでよい。ノンブロッキングなので、
+// UpdateFadeと同様にメインループからUpdateを呼び出す。
+type MessageQueue struct {
+	device   *Device
+	display  int
+	messages []Message
+	playing  bool
+	started  uint32
+	duration time.Duration
+	toggled  uint32
+	// enabled is whether the display was enabled when the message started,
+	// and blanked is set while the flash has blanked it.
+	// enabledはメッセージの開始時にディスプレイが有効だったかを表し、
+	// blankedは点滅がディスプレイを消している間に設定する。
+	enabled bool
+	blanked bool
+}
+
+// NewMessageQueue creates an empty MessageQueue for a display.
+//
+// NewMessageQueueは、ディスプレイ用の空のMessageQueueを作る。
+func NewMessageQueue(device *Device, display int) *MessageQueue {
+	return &MessageQueue{device: device, display: display}
+}
+
+// Enqueue adds a message to the end of the queue.
+//
+// Enqueueは、キューの最後にメッセージを追加する。
+func (q *MessageQueue) Enqueue(m Message) {
+	q.messages = append(q.messages, m)
+}
+
+// Len returns the number of messages waiting or playing.
+//
+// Lenは、待っているか再生中のメッセージの数を返す。
+func (q *MessageQueue) Len() int {
+	return len(q.messages)
+}
+
+// Clear drops all messages and stops the one playing, leaving its current
+// frame shown.
+//
+// Clearは、すべてのメッセージを捨てて再生中のものを止め、現在のフレームを
+// 表示したままにする。
+func (q *MessageQueue) Clear() {
+	q.stop()
+	q.messages = q.messages[:0]
+}
+
+// Update plays the queue: it starts the next message when the current one
+// has been shown for its duration, and drives the scroll and flash effects.
+// It returns true while a message is playing.
+//
+// Updateはキューを再生する。現在のメッセージをその時間だけ表示したら次の
+// メッセージを始め、スクロールと点滅の効果を動かす。メッセージの再生中は
+// trueを返す。
+func (q *MessageQueue) Update() bool {
+	if q.playing && q.device.sinceTick(q.started) >= q.duration {
+		q.stop()
+		q.messages = q.messages[1:]
+	}
+	if !q.playing {
+		if len(q.messages) == 0 {
+			return false
+		}
+		q.start()
+	}
+	d := q.device
+	switch m := q.messages[0]; m.Effect {
+	case EffectScroll:
+		d.UpdateScroll()
+	case EffectFlash:
+		if q.device.sinceTick(q.toggled) >= interval(m.Interval, defaultFlashInterval) {
+			q.toggled = q.device.tick()
+			q.blanked = !q.blanked
+			d.enableDisplay(q.display, q.enabled && !q.blanked)
+			d.Display()
+		}
+	}
+	return true
+}
+
+// start shows the first message of the queue.
+//
+// startは、キューの最初のメッセージを表示する。
+func (q *MessageQueue) start() {
+	d := q.device
+	m := q.messages[0]
+	q.playing = true
+	q.started = q.device.tick()
+	q.toggled = q.started
+	q.enabled = d.IsDisplayEnabled(q.display)
+	q.duration = m.Duration
+	switch m.Effect {
+	case EffectScroll:
+		step := interval(m.Interval, defaultScrollInterval)
+		d.StartScroll(q.display, m.Text, step)
+		if q.duration == 0 {
+			cells, _, _ := d.layoutString(m.Text)
+			q.duration = time.Duration(len(cells)+1) * step
+		}
+	default:
+		autoFlush := d.autoFlush
+		d.autoFlush = false
+		d.WriteString(q.display, m.Text)
+		d.autoFlush = autoFlush
+		d.Display()
+	}
+}
+
+// stop ends the effect of the playing message.
+//
+// stopは、再生中のメッセージの効果を終える。
+func (q *MessageQueue) stop() {
+	if !q.playing {
+		return
+	}
+	q.playing = false
+	q.device.StopScroll(q.display)
+	// Only undo the flash, so that a display the application has disabled
+	// stays so.
+	if q.blanked {
+		q.blanked = false
+		q.device.enableDisplay(q.display, q.enabled)
+		q.device.Display()
+	}
+}
+
+// interval returns v, or def if v is 0.
+//
+// intervalは、vを返す。vが0ならdefを返す。
+func interval(v, def time.Duration) time.Duration {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
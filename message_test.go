@@ -0,0 +1,105 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// expireMessage makes the playing message of a queue reach its duration.
+func expireMessage(q *MessageQueue) {
+	advanceClock(q.device, time.Hour)
+}
+
+// TestMessageQueue verifies that messages play in order with their effects.
+func TestMessageQueue(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	queue := NewMessageQueue(device, 0)
+
+	if queue.Update() {
+		t.Errorf("FAIL: Update() of an empty queue should return false")
+	}
+
+	queue.Enqueue(Message{Text: "HELLO", Duration: time.Second})
+	queue.Enqueue(Message{Text: "ALERT", Effect: EffectFlash, Duration: time.Second})
+	queue.Enqueue(Message{Text: "SCROLLING TEXT", Effect: EffectScroll})
+	if queue.Len() != 3 {
+		t.Errorf("FAIL: Len() is wrong!\nExpected: %d\nGot:      %d", 3, queue.Len())
+	}
+
+	if !queue.Update() {
+		t.Fatalf("FAIL: Update() should return true while playing")
+	}
+	assertDisplay(t, device, 0, "HELLO")
+
+	expireMessage(queue)
+	queue.Update()
+	assertDisplay(t, device, 0, "ALERT")
+	advanceClock(device, defaultFlashInterval)
+	queue.Update()
+	if device.IsDisplayEnabled(0) {
+		t.Errorf("FAIL: Flashing message should blank the display")
+	}
+
+	expireMessage(queue)
+	queue.Update()
+	if !device.IsDisplayEnabled(0) {
+		t.Errorf("FAIL: Display should be enabled again after a flashing message")
+	}
+	if !device.IsScrolling() {
+		t.Errorf("FAIL: Scrolling message should start a scroll")
+	}
+	if queue.duration != 15*defaultScrollInterval {
+		t.Errorf("FAIL: Scroll duration is wrong!\nExpected: %v\nGot:      %v", 15*defaultScrollInterval, queue.duration)
+	}
+
+	expireMessage(queue)
+	if queue.Update() {
+		t.Errorf("FAIL: Update() should return false after the last message")
+	}
+	if device.IsScrolling() || queue.Len() != 0 {
+		t.Errorf("FAIL: Queue should be empty and stopped")
+	}
+}
+
+// TestMessageQueueClear verifies that Clear stops the playing message.
+func TestMessageQueueClear(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	queue := NewMessageQueue(device, 1)
+	queue.Enqueue(Message{Text: "FLASH", Effect: EffectFlash, Duration: time.Second})
+	queue.Update()
+	advanceClock(device, defaultFlashInterval)
+	queue.Update()
+
+	queue.Clear()
+	if queue.Len() != 0 || queue.Update() {
+		t.Errorf("FAIL: Queue should be empty after Clear")
+	}
+	if !device.IsDisplayEnabled(1) {
+		t.Errorf("FAIL: Display should be enabled again after Clear")
+	}
+}
+
+// TestMessageQueueDisabledDisplay verifies that the queue leaves a display disabled by the application as it was.
+func TestMessageQueueDisabledDisplay(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.EnableDisplay(0, false)
+	queue := NewMessageQueue(device, 0)
+	queue.Enqueue(Message{Text: "HELLO", Duration: time.Second})
+	queue.Enqueue(Message{Text: "ALERT", Effect: EffectFlash, Duration: time.Second})
+
+	queue.Update()
+	expireMessage(queue)
+	queue.Update()
+	for i := 0; i < 3; i++ {
+		advanceClock(device, defaultFlashInterval)
+		queue.Update()
+		if device.IsDisplayEnabled(0) {
+			t.Fatalf("FAIL: Flashing message should not enable a disabled display")
+		}
+	}
+
+	expireMessage(queue)
+	if queue.Update() || device.IsDisplayEnabled(0) {
+		t.Errorf("FAIL: Display disabled by the application should stay disabled after the queue")
+	}
+}
@@ -0,0 +1,145 @@
+package ht16k33
+
+import (
+	"math"
+	"time"
+)
+
+// Timing of the peak of a LevelMeter: it holds for peakHold, then falls at
+// a quarter of the decay speed.
+const (
+	peakHold        = time.Second
+	peakDecayFactor = 4
+)
+
+// LevelMeter is a VU meter: a bar across a display that follows the levels
+// given by SetLevel, rising within the attack time and falling within the
+// decay time, with a peak segment that holds the highest level for a while
+// and then falls slowly. It runs as an Animation, so call Update from the
+// main loop.
+//
+// LevelMeterはVUメーター。SetLevelで与えたレベルにディスプレイいっぱいの
+// バーが追従し、attackの時間で上がり、decayの時間で下がる。ピークの
+// セグメントは最も高いレベルをしばらく保ってからゆっくり下がる。Animation
+// として動くので、メインループからUpdateを呼び出す。
+type LevelMeter struct {
+	device  *Device
+	display int
+	// attack and decay are the times the bar takes to rise and to fall over
+	// the full scale; 0 follows at once.
+	// attackとdecayは、バーが全体を上がる時間と下がる時間。0ならすぐに
+	// 追従する。
+	attack, decay time.Duration
+	target        float64
+	level         float64
+	peak          float64
+	peakTime      uint32
+	last          uint32
+	running       bool
+}
+
+// NewLevelMeter creates a LevelMeter on a display, starting at 0.
+//
+// NewLevelMeterは、ディスプレイに0から始まるLevelMeterを作る。
+func NewLevelMeter(device *Device, display int, attack, decay time.Duration) *LevelMeter {
+	return &LevelMeter{device: device, display: display, attack: attack, decay: decay}
+}
+
+// SetLevel sets the level (0 to 1) the bar moves to. Values outside 0 to 1
+// are clamped.
+//
+// SetLevelは、バーが向かうレベル(0から1)を設定する。0から1の範囲外の値は
+// 制限する。
+func (m *LevelMeter) SetLevel(level float64) {
+	if !(level > 0) {
+		level = 0
+	}
+	m.target = math.Min(level, 1)
+	if !m.running {
+		m.device.Animate(m)
+	}
+}
+
+// Level returns the level the bar shows now.
+//
+// Levelは、バーが現在表示しているレベルを返す。
+func (m *LevelMeter) Level() float64 {
+	return m.level
+}
+
+// Peak returns the level the peak segment shows now.
+//
+// Peakは、ピークのセグメントが現在表示しているレベルを返す。
+func (m *LevelMeter) Peak() float64 {
+	return m.peak
+}
+
+// Start begins moving the bar to the level.
+func (m *LevelMeter) Start(d *Device, now time.Time) {
+	m.running = true
+	m.last = tickOf(now)
+}
+
+// Step moves the bar and the peak for the time since the last step. The
+// meter rests once both have settled, until the next SetLevel.
+func (m *LevelMeter) Step(d *Device, now time.Time) bool {
+	tick := tickOf(now)
+	dt := tickDuration(tick - m.last)
+	m.last = tick
+	if m.level < m.target {
+		m.level = math.Min(m.level+meterRate(dt, m.attack), m.target)
+	} else {
+		m.level = math.Max(m.level-meterRate(dt, m.decay), m.target)
+	}
+	if m.level >= m.peak {
+		m.peak = m.level
+		m.peakTime = tick
+	} else if tickDuration(tick-m.peakTime) >= peakHold {
+		m.peak = math.Max(m.peak-meterRate(dt, m.decay*peakDecayFactor), m.level)
+	}
+	m.running = m.level != m.target || m.peak != m.level
+
+	before := d.buffer
+	m.draw(d)
+	return d.buffer != before
+}
+
+// Stop moves the bar and the peak to the level at once.
+func (m *LevelMeter) Stop(d *Device) {
+	m.level, m.peak = m.target, m.target
+	m.running = false
+	m.draw(d)
+}
+
+// draw draws the bar and the peak segment on the display.
+//
+// drawは、ディスプレイにバーとピークのセグメントを描く。
+func (m *LevelMeter) draw(d *Device) {
+	d.clearDisplay(m.display)
+	steps := d.progressSteps(m.level)
+	d.drawBar(m.display, steps)
+	if peak := d.progressSteps(m.peak) - 1; peak >= steps {
+		levels := len(progressLevels)
+		segment := progressLevels[peak%levels]
+		if peak%levels > 0 {
+			segment &^= progressLevels[peak%levels-1]
+		}
+		d.orPattern(m.display, peak/levels, segment, false)
+	}
+}
+
+// Done reports whether the meter has settled.
+func (m *LevelMeter) Done() bool {
+	return !m.running
+}
+
+// meterRate returns the share of the full scale moved in dt by a meter that
+// takes span to move over all of it.
+//
+// meterRateは、全体を動くのにspanかかるメーターがdtで動く全体の割合を返す。
+func meterRate(dt, span time.Duration) float64 {
+	if span <= 0 {
+		return 1
+	}
+	return float64(dt) / float64(span)
+}
@@ -0,0 +1,54 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestLevelMeter verifies the attack and decay speeds, the peak hold and that the meter rests when settled.
+func TestLevelMeter(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	meter := NewLevelMeter(device, 0, 100*time.Millisecond, time.Second)
+
+	meter.SetLevel(1)
+	start := device.animationTime()
+	device.animator.Step(device, start.Add(50*time.Millisecond))
+	if meter.Level() != 0.5 {
+		t.Errorf("FAIL: Level should rise by half in half the attack time, got %v", meter.Level())
+	}
+	device.animator.Step(device, start.Add(100*time.Millisecond))
+	if meter.Level() != 1 || meter.Peak() != 1 {
+		t.Errorf("FAIL: Level should reach 1 after the attack time, got %v", meter.Level())
+	}
+
+	// The bar falls, the peak holds on the top segment of the last digit.
+	meter.SetLevel(0)
+	start = device.animationTime()
+	device.animator.Step(device, start.Add(500*time.Millisecond))
+	if meter.Level() != 0.5 || meter.Peak() != 1 {
+		t.Errorf("FAIL: Level should fall to 0.5 while the peak holds, got %v and %v", meter.Level(), meter.Peak())
+	}
+	if got := device.digitPattern(0, 7); got != segA {
+		t.Errorf("FAIL: Peak segment is wrong!\nExpected: %x\nGot:      %x", segA, got)
+	}
+	if got := device.digitPattern(0, 3); got != segD|segG|segA {
+		t.Errorf("FAIL: Bar should fill half of the display, got %x", got)
+	}
+
+	// After the hold, the peak falls at a quarter of the decay speed.
+	device.animator.Step(device, start.Add(1050*time.Millisecond))
+	device.animator.Step(device, start.Add(2050*time.Millisecond))
+	if meter.Level() != 0 || meter.Peak() != 0.75 {
+		t.Errorf("FAIL: Peak should fall slowly, got %v", meter.Peak())
+	}
+	device.animator.Step(device, start.Add(5100*time.Millisecond))
+	if device.IsAnimating() {
+		t.Errorf("FAIL: Meter should rest when settled")
+	}
+	assertDisplay(t, device, 0, "")
+
+	meter.SetLevel(2)
+	if !device.IsAnimating() {
+		t.Errorf("FAIL: SetLevel() should start the meter again")
+	}
+}
@@ -0,0 +1,904 @@
+package ht16k33
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrOverflow is returned by the numeric writers when a value cannot be
+// shown within the width of the display.
+var ErrOverflow = errors.New("ht16k33: value does not fit on the display")
+
+// OverflowStrategy decides what the numeric writers do with a value that
+// does not fit on the display.
+//
+// OverflowStrategyは、ディスプレイに収まらない値を数値を書き込むメソッドが
+// どう扱うかを決める。
+type OverflowStrategy uint8
+
+const (
+	// OverflowError leaves the display unchanged and returns ErrOverflow
+	// (default).
+	OverflowError OverflowStrategy = iota
+	// OverflowScientific shows the value in scientific notation, such as
+	// "1.2E7" or "-4.5E-9", with as many digits as fit.
+	OverflowScientific
+	// OverflowClamp shows the closest value that fits, such as "99999999"
+	// or "-9999999" on an 8-digit display.
+	OverflowClamp
+	// OverflowIndicator shows the indicator set by SetOverflowIndicator,
+	// which is a row of '-' across the display by default.
+	OverflowIndicator
+)
+
+// SetOverflowStrategy sets what WriteFloat, WriteFixed and WriteInt do with
+// values that do not fit on the display.
+//
+// SetOverflowStrategyは、ディスプレイに収まらない値をWriteFloat、
+// WriteFixed、WriteIntがどう扱うかを設定する。
+func (d *Device) SetOverflowStrategy(strategy OverflowStrategy) {
+	d.overflow = strategy
+}
+
+// SetOverflowIndicator sets the text shown for values that do not fit when
+// the OverflowStrategy is OverflowIndicator, such as "OF" or "Err". It is
+// right-aligned like numbers and cut to the display width. An empty text
+// restores the default row of '-'.
+//
+// SetOverflowIndicatorは、OverflowStrategyがOverflowIndicatorのときに収まら
+// ない値の代わりに表示するテキスト("OF"や"Err"など)を設定する。数値と同じ
+// ように右詰めにし、ディスプレイの幅で切り詰める。空のテキストを渡すと
+// デフォルトの'-'の並びに戻る。
+func (d *Device) SetOverflowIndicator(text string) {
+	d.overflowIndicator = text
+}
+
+// SignPlacement decides where the numeric writers put the minus sign of a
+// negative number.
+//
+// SignPlacementは、数値を書き込むメソッドが負の数のマイナス記号をどこに置く
+// かを決める。
+type SignPlacement uint8
+
+const (
+	// SignLeading puts '-' right before the first digit, as in "     -42"
+	// (default).
+	SignLeading SignPlacement = iota
+	// SignLeft puts '-' on the leftmost digit, as in "-     42", like many
+	// panel meters.
+	SignLeft
+)
+
+// SetSignPlacement sets where the minus sign of negative numbers is shown.
+//
+// SetSignPlacementは、負の数のマイナス記号を表示する位置を設定する。
+func (d *Device) SetSignPlacement(placement SignPlacement) {
+	d.sign = placement
+}
+
+// Padding decides what fills the digits left of a right-aligned number.
+//
+// Paddingは、右詰めの数値の左側の桁を何で埋めるかを決める。
+type Padding uint8
+
+const (
+	// PadBlank leaves the digits left of the number blank, as in "      42",
+	// which suits meters (default).
+	PadBlank Padding = iota
+	// PadZero fills them with zeros, as in "00000042" or "-0000042", which
+	// suits counters and clocks. The sign always goes on the leftmost digit.
+	PadZero
+)
+
+// SetPadding sets what fills the unused digits left of numbers written by
+// WriteInt, WriteFloat, WriteFixed, WriteHex, WriteBin and WriteOct.
+//
+// SetPaddingは、WriteInt、WriteFloat、WriteFixed、WriteHex、WriteBin、
+// WriteOctで書き込む数値の左側の使わない桁を何で埋めるかを設定する。
+func (d *Device) SetPadding(padding Padding) {
+	d.padding = padding
+}
+
+// SetGrouping turns thousands separators on or off for WriteInt and
+// WriteInt16. The separators use the DP segments, so "1234567" is shown as
+// "1.234.567" without taking up more digits.
+//
+// SetGroupingは、WriteIntとWriteInt16の3桁区切りをオンまたはオフにする。
+// 区切りはDPセグメントを使うので、"1234567"は桁を増やさずに"1.234.567"と
+// 表示される。
+func (d *Device) SetGrouping(enabled bool) {
+	d.grouping = enabled
+}
+
+// groupThousands inserts a '.' every three digits from the right of an
+// integer.
+//
+// groupThousandsは、整数の右から3桁ごとに'.'を挿入する。
+func groupThousands(text string) string {
+	sign := ""
+	if text[0] == '-' {
+		sign, text = "-", text[1:]
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		if i > 0 && (len(text)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteByte(text[i])
+	}
+	return sign + b.String()
+}
+
+// zeroCell is a '0' drawn with its own pattern, so padding works with any
+// font.
+//
+// zeroCellは、どのフォントでも埋められるように独自のパターンで描いた'0'。
+var zeroCell = cell{pattern: segA | segB | segC | segD | segE | segF}
+
+// padCells inserts zeros at index at so that cells fill the display, if
+// the padding is PadZero.
+//
+// padCellsは、パディングがPadZeroなら、cellsがディスプレイを埋めるように
+// 位置atに0を挿入する。
+func (d *Device) padCells(display int, cells []cell, at int) []cell {
+	n := d.width(display) - len(cells)
+	if d.padding != PadZero || n <= 0 {
+		return cells
+	}
+	padded := make([]cell, 0, len(cells)+n)
+	padded = append(padded, cells[:at]...)
+	for i := 0; i < n; i++ {
+		padded = append(padded, zeroCell)
+	}
+	return append(padded, cells[at:]...)
+}
+
+// writeOverflow handles v, which does not fit as a plain number, according
+// to the OverflowStrategy.
+//
+// writeOverflowは、普通の数値としては収まらないvをOverflowStrategyに従って
+// 扱う。
+func (d *Device) writeOverflow(display int, v float64) error {
+	if d.overflow == OverflowScientific {
+		if text, ok := formatScientific(v, d.width(display)); ok {
+			return d.writeNumber(display, text)
+		}
+		return ErrOverflow
+	}
+	return d.writeOverflowLimit(display, v < 0)
+}
+
+// writeFixedOverflow is writeOverflow for the text of formatFixed, so that
+// WriteFixed needs no floating-point math.
+//
+// writeFixedOverflowは、formatFixedのテキストに対するwriteOverflowで、
+// WriteFixedが浮動小数点の計算を必要としないようにする。
+func (d *Device) writeFixedOverflow(display int, text string) error {
+	if d.overflow == OverflowScientific {
+		if text, ok := fixedScientific(text, d.width(display)); ok {
+			return d.writeNumber(display, text)
+		}
+		return ErrOverflow
+	}
+	return d.writeOverflowLimit(display, text[0] == '-')
+}
+
+// writeOverflowLimit handles the strategies other than OverflowScientific,
+// which only need the sign of the number.
+//
+// writeOverflowLimitは、OverflowScientific以外の方法を扱う。これらは数値の
+// 符号だけを必要とする。
+func (d *Device) writeOverflowLimit(display int, negative bool) error {
+	width := d.width(display)
+	switch d.overflow {
+	case OverflowClamp:
+		if negative {
+			return d.writeNumber(display, "-"+strings.Repeat("9", width-1))
+		}
+		return d.writeNumber(display, strings.Repeat("9", width))
+	case OverflowIndicator:
+		text := d.overflowIndicator
+		if text == "" {
+			text = strings.Repeat("-", width)
+		}
+		return d.writeIndicator(display, text)
+	}
+	return ErrOverflow
+}
+
+// writeIndicator clears the display and writes text right-aligned, cut to
+// the display width.
+//
+// writeIndicatorは、ディスプレイをクリアし、textをディスプレイの幅で切り
+// 詰めて右詰めで書き込む。
+func (d *Device) writeIndicator(display int, text string) error {
+	cells, _, err := d.layoutString(text)
+	if err != nil {
+		return err
+	}
+	if width := d.width(display); len(cells) > width {
+		cells = cells[:width]
+	}
+	d.writeRightAligned(display, cells)
+	return nil
+}
+
+// SetRange sets the range of values that WriteFloat, WriteFixed and WriteInt
+// show. Below min they show "LO" and above max "HI", like panel meters,
+// instead of a misleading value (see SetRangeIndicators). If min is greater
+// than max, the range is removed.
+//
+// SetRangeは、WriteFloat、WriteFixed、WriteIntが表示する値の範囲を設定する。
+// パネルメーターのように、誤解を招く値の代わりに、minより小さければ"LO"、
+// maxより大きければ"HI"を表示する(SetRangeIndicatorsを参照)。minがmaxより
+// 大きければ範囲を解除する。
+func (d *Device) SetRange(min, max float64) {
+	d.rangeSet = min <= max
+	d.rangeMin, d.rangeMax = min, max
+	d.rangeDecimals = -1
+}
+
+// SetRangeIndicators sets the texts shown for values outside the range set
+// by SetRange. Empty texts restore the defaults "LO" and "HI".
+//
+// SetRangeIndicatorsは、SetRangeで設定した範囲外の値の代わりに表示する
+// テキストを設定する。空のテキストを渡すとデフォルトの"LO"と"HI"に戻る。
+func (d *Device) SetRangeIndicators(lo, hi string) {
+	d.rangeLo, d.rangeHi = lo, hi
+}
+
+// writeOutOfRange shows the range indicator if v is outside the range set by
+// SetRange, and returns true if it did.
+//
+// writeOutOfRangeは、vがSetRangeで設定した範囲外なら範囲外の表示をし、
+// その場合はtrueを返す。
+func (d *Device) writeOutOfRange(display int, v float64) (bool, error) {
+	if !d.rangeSet {
+		return false, nil
+	}
+	return d.writeRangeIndicator(display, v < d.rangeMin, v > d.rangeMax)
+}
+
+// writeFixedOutOfRange is writeOutOfRange for the scaled integer of
+// WriteFixed. It compares value with the limits of SetRange scaled by
+// 10^decimals, which are only worked out again when decimals changes, so
+// the value itself is never converted to floating point.
+//
+// writeFixedOutOfRangeは、WriteFixedの倍率を掛けた整数に対する
+// writeOutOfRange。valueをSetRangeの範囲に10^decimalsを掛けたものと比べる。
+// 範囲はdecimalsが変わったときだけ計算し直すので、値自体を浮動小数点に
+// 変換することはない。
+func (d *Device) writeFixedOutOfRange(display int, value int64, decimals int) (bool, error) {
+	if !d.rangeSet {
+		return false, nil
+	}
+	decimals = max(decimals, 0) // As formatFixed
+	if decimals != d.rangeDecimals {
+		scale := math.Pow10(decimals)
+		// An integer is below min*scale exactly when it is below its ceiling.
+		d.rangeFixedMin = scaledLimit(math.Ceil(d.rangeMin * scale))
+		d.rangeFixedMax = scaledLimit(math.Floor(d.rangeMax * scale))
+		d.rangeDecimals = decimals
+	}
+	return d.writeRangeIndicator(display, value < d.rangeFixedMin, value > d.rangeFixedMax)
+}
+
+// scaledLimit converts a scaled range limit to an int64, saturating at the
+// ends of its range.
+//
+// scaledLimitは、倍率を掛けた範囲の限界をint64に変換し、その範囲の端で
+// 飽和させる。
+func scaledLimit(v float64) int64 {
+	switch {
+	case v >= math.MaxInt64:
+		return math.MaxInt64
+	case v <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(v)
+}
+
+// writeRangeIndicator shows the indicator for a value below or above the
+// range, and returns true if it did.
+//
+// writeRangeIndicatorは、範囲より小さい、または大きい値の表示をし、その
+// 場合はtrueを返す。
+func (d *Device) writeRangeIndicator(display int, below, above bool) (bool, error) {
+	switch {
+	case below:
+		text := d.rangeLo
+		if text == "" {
+			text = "LO"
+		}
+		return true, d.writeIndicator(display, text)
+	case above:
+		text := d.rangeHi
+		if text == "" {
+			text = "HI"
+		}
+		return true, d.writeIndicator(display, text)
+	}
+	return false, nil
+}
+
+// formatScientific formats v in compact scientific notation ("1.2E7") with
+// the shortest exact mantissa if it fits, or else the most precision that
+// fits in width digits.
+//
+// formatScientificは、vを簡潔な指数表記("1.2E7")に整形する。仮数の最短の
+// 正確な表現が収まればそれを使い、収まらなければwidth桁に収まる最大の精度
+// を使う。
+func formatScientific(v float64, width int) (string, bool) {
+	if text := compactExponent(strconv.FormatFloat(v, 'e', -1, 64)); numberWidth(text) <= width {
+		return text, true
+	}
+	for prec := width; prec >= 0; prec-- {
+		if text := compactExponent(strconv.FormatFloat(v, 'e', prec, 64)); numberWidth(text) <= width {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// fixedScientific is formatScientific for the text of formatFixed, working
+// on its decimal digits instead of on a float64.
+//
+// fixedScientificは、formatFixedのテキストに対するformatScientificで、
+// float64ではなくその10進数の桁を扱う。
+func fixedScientific(text string, width int) (string, bool) {
+	sign := ""
+	if text[0] == '-' {
+		sign, text = "-", text[1:]
+	}
+	point := strings.IndexByte(text, '.')
+	if point < 0 {
+		point = len(text)
+	}
+	digits := strings.Replace(text, ".", "", 1)
+	trimmed := strings.TrimLeft(digits, "0")
+	exp := point - (len(digits) - len(trimmed)) - 1
+	digits = strings.TrimRight(trimmed, "0")
+	if digits == "" {
+		digits, exp = "0", 0
+	}
+	// The shortest exact mantissa first, then fewer and fewer digits.
+	for n := len(digits); n > 0; n-- {
+		mantissa, carried := roundDigits(digits, n)
+		e := exp
+		if carried {
+			e++
+		}
+		text := sign + mantissa[:1]
+		if len(mantissa) > 1 {
+			text += "." + mantissa[1:]
+		}
+		if text += "E" + strconv.Itoa(e); numberWidth(text) <= width {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// roundDigits rounds a string of decimal digits to its first n digits,
+// half away from zero, and reports whether it carried into a new leading
+// digit, as "996" does to "10" for n of 2.
+//
+// roundDigitsは、10進数の桁の文字列を先頭のn桁に0から遠い方へ四捨五入し、
+// 新しい先頭の桁へ繰り上がったかを報告する。たとえばnが2なら"996"は"10"に
+// なり、繰り上がる。
+func roundDigits(digits string, n int) (string, bool) {
+	if len(digits) <= n {
+		return digits, false
+	}
+	b := []byte(digits[:n])
+	if digits[n] < '5' {
+		return string(b), false
+	}
+	i := n - 1
+	for ; i >= 0 && b[i] == '9'; i-- {
+		b[i] = '0'
+	}
+	if i < 0 {
+		return "1" + string(b[:n-1]), true
+	}
+	b[i]++
+	return string(b), false
+}
+
+// compactExponent turns Go's "-1.23e+07" into "-1.23E7".
+//
+// compactExponentは、Goの"-1.23e+07"を"-1.23E7"にする。
+func compactExponent(text string) string {
+	e := strings.IndexByte(text, 'e')
+	exp, err := strconv.Atoi(text[e+1:])
+	if err != nil {
+		return text
+	}
+	return text[:e] + "E" + strconv.Itoa(exp)
+}
+
+// WriteFloat displays a floating-point number right-aligned on one of the
+// two displays, with prec digits after the decimal point. The decimal point
+// uses the DP segment, so it does not take up a digit. If the integer part
+// needs more digits, the precision is reduced until the number fits; if it
+// does not fit even without decimals, the OverflowStrategy decides what is
+// shown (by default the display is left unchanged and ErrOverflow is
+// returned). With NotationSI, the value is scaled by an SI prefix first (see
+// SetNotation) and prec applies to the scaled value.
+//
+// WriteFloatは、2つのディスプレイのいずれかに浮動小数点数を右詰めで表示し、
+// 小数点以下をprec桁にする。小数点はDPセグメントを使うので桁を使わない。
+// 整数部にもっと桁が必要な場合は、収まるまで精度を下げる。小数なしでも収ま
+// らない場合は、OverflowStrategyに従う(デフォルトではディスプレイを変更せず
+// にErrOverflowを返す)。NotationSIの場合は、先にSI接頭辞で値を換算し
+// (SetNotationを参照)、precは換算後の値に適用する。
+func (d *Device) WriteFloat(display int, v float64, prec int) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if math.IsNaN(v) {
+		return ErrOverflow
+	}
+	if handled, err := d.writeOutOfRange(display, v); handled {
+		return err
+	}
+	if math.IsInf(v, 0) {
+		return ErrOverflow
+	}
+	if d.notation == NotationSI {
+		if text, prefix, ok := d.formatSI(v, prec); ok {
+			return d.writeNumberSuffix(display, text, []cell{prefix})
+		}
+	}
+	if text, ok := d.formatFloat(v, prec, d.digits); ok {
+		return d.writeNumber(display, text)
+	}
+	return d.writeOverflow(display, v)
+}
+
+// RoundingMode decides how the numeric writers round values to the digits
+// they show.
+//
+// RoundingModeは、数値を書き込むメソッドが表示する桁に値をどう丸めるかを
+// 決める。
+type RoundingMode uint8
+
+const (
+	// RoundNearest rounds to the nearest value of the binary float, with
+	// ties to even, like strconv (default). 2.675 is really 2.67499..., so
+	// it becomes "2.67".
+	RoundNearest RoundingMode = iota
+	// RoundHalfUp rounds the shortest decimal form of the value half away
+	// from zero, so 2.675 becomes "2.68" and -2.5 becomes "-3".
+	RoundHalfUp
+	// RoundHalfEven rounds the shortest decimal form of the value half to
+	// even (banker's rounding), so 2.5 becomes "2" and 3.5 becomes "4".
+	RoundHalfEven
+	// RoundTruncate drops the extra digits, rounding toward zero, so a
+	// meter never shows more than it measured.
+	RoundTruncate
+)
+
+// SetRounding sets how WriteFloat, WriteTemperature, WritePercent and
+// WriteTemplate round values.
+//
+// SetRoundingは、WriteFloat、WriteTemperature、WritePercent、WriteTemplate
+// で値をどう丸めるかを設定する。
+func (d *Device) SetRounding(mode RoundingMode) {
+	d.rounding = mode
+}
+
+// formatRounded formats v with prec digits after the decimal point, rounded
+// by mode.
+//
+// formatRoundedは、vを小数点以下prec桁でmodeに従って丸めて整形する。
+func formatRounded(v float64, prec int, mode RoundingMode) string {
+	if mode == RoundNearest {
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+	text := strconv.FormatFloat(v, 'f', -1, 64)
+	sign := ""
+	if text[0] == '-' {
+		sign, text = "-", text[1:]
+	}
+	intPart, fracPart := text, ""
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		intPart, fracPart = text[:i], text[i+1:]
+	}
+	if len(fracPart) <= prec {
+		fracPart += strings.Repeat("0", prec-len(fracPart))
+		return joinDecimal(sign, intPart+fracPart, prec)
+	}
+	digits := []byte(intPart + fracPart[:prec])
+	rest := fracPart[prec:]
+	up := false
+	switch mode {
+	case RoundHalfUp:
+		up = rest[0] >= '5'
+	case RoundHalfEven:
+		odd := (digits[len(digits)-1]-'0')%2 == 1
+		up = rest[0] > '5' || (rest[0] == '5' && (strings.Trim(rest[1:], "0") != "" || odd))
+	}
+	if up {
+		i := len(digits) - 1
+		for ; i >= 0 && digits[i] == '9'; i-- {
+			digits[i] = '0'
+		}
+		if i < 0 {
+			digits = append([]byte{'1'}, digits...)
+		} else {
+			digits[i]++
+		}
+	}
+	return joinDecimal(sign, string(digits), prec)
+}
+
+// joinDecimal puts the decimal point before the last prec digits.
+//
+// joinDecimalは、最後のprec桁の前に小数点を置く。
+func joinDecimal(sign string, digits string, prec int) string {
+	if prec == 0 {
+		return sign + digits
+	}
+	point := len(digits) - prec
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// Notation decides how WriteFloat writes numbers.
+//
+// Notationは、WriteFloatで数値をどう書くかを決める。
+type Notation uint8
+
+const (
+	// NotationPlain writes numbers as they are (default).
+	NotationPlain Notation = iota
+	// NotationSI scales numbers by an SI prefix from n to G and shows it
+	// after them, so 4700 is shown as "4.7k". The prefixes are drawn with
+	// their own patterns: 'm' is an 'n' with a bar on top to tell it from
+	// nano, and 'M' looks like an upside-down 'U'.
+	NotationSI
+)
+
+// SetNotation sets how WriteFloat writes numbers, for example with SI
+// prefixes for frequency counters and component testers.
+//
+// SetNotationは、WriteFloatで数値をどう書くかを設定する。例えば周波数カウンタ
+// や部品テスタ向けにSI接頭辞を使える。
+func (d *Device) SetNotation(notation Notation) {
+	d.notation = notation
+}
+
+// siPrefixes are the SI prefixes from 1e-9 to 1e9, in steps of 1e3.
+//
+// siPrefixesは、1e-9から1e9まで1e3ごとのSI接頭辞。
+var siPrefixes = [...]cell{
+	{pattern: segC | segE | segG},        // n
+	{pattern: segB | segE | segF | segG}, // µ
+	{pattern: segA | segC | segE | segG}, // m
+	{},                                   // No prefix
+	{pattern: segA | segC | segE | segF | segG}, // k
+	{pattern: segA | segB | segC | segE | segF}, // M
+	{pattern: segA | segC | segD | segE | segF}, // G
+}
+
+// formatSI scales v into the range 1-1000 and formats it like formatFloat,
+// leaving a digit for its SI prefix. The prefix is chosen after rounding,
+// so that 999999 with 2 decimals becomes "1.00" with M rather than
+// "1000.00" with k. It returns false if v is 0, needs no prefix or one out
+// of range, or does not fit.
+//
+// formatSIは、vを1-1000の範囲に換算し、SI接頭辞の1桁を残してformatFloat
+// のように整形する。接頭辞は丸めた後に選ぶので、999999を小数2桁にすると
+// "1000.00"とkではなく"1.00"とMになる。vが0の場合、接頭辞が不要または
+// 範囲外の場合、収まらない場合はfalseを返す。
+func (d *Device) formatSI(v float64, prec int) (string, cell, bool) {
+	if v == 0 {
+		return "", cell{}, false
+	}
+	for exp := int(math.Floor(math.Log10(math.Abs(v)) / 3)); ; exp++ {
+		index := exp + len(siPrefixes)/2
+		if index < 0 || index >= len(siPrefixes) {
+			return "", cell{}, false
+		}
+		text, ok := d.formatFloat(v/math.Pow10(exp*3), prec, d.digits-1)
+		if !ok {
+			return "", cell{}, false
+		}
+		// Rounding can carry the scaled value up to 1000, the next prefix.
+		if scaled, _ := strconv.ParseFloat(text, 64); math.Abs(scaled) < 1000 {
+			return text, siPrefixes[index], exp != 0
+		}
+	}
+}
+
+// formatFloat formats v with prec digits after the decimal point, rounded
+// by the RoundingMode, reducing the precision until it fits in width digits.
+//
+// formatFloatは、vを小数点以下prec桁でRoundingModeに従って丸めて整形し、
+// width桁に収まるまで精度を下げる。
+func (d *Device) formatFloat(v float64, prec int, width int) (string, bool) {
+	if prec < 0 {
+		prec = 0
+	}
+	for p := prec; p >= 0; p-- {
+		text := trimNegativeZero(formatRounded(v, p, d.rounding))
+		if numberWidth(text) <= width {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// trimNegativeZero removes the sign of a number that was rounded to zero,
+// so that -0.001 is shown as "0.00" rather than "-0.00".
+//
+// trimNegativeZeroは、0に丸められた数値の符号を取り除き、-0.001が"-0.00"
+// ではなく"0.00"と表示されるようにする。
+func trimNegativeZero(text string) string {
+	if len(text) == 0 || text[0] != '-' || strings.Trim(text[1:], "0.") != "" {
+		return text
+	}
+	return text[1:]
+}
+
+// WriteInt displays an integer right-aligned on one of the two displays.
+// Negative numbers get a minus sign placed according to SetSignPlacement.
+// If the number does not fit, the OverflowStrategy decides what is shown.
+//
+// WriteIntは、2つのディスプレイのいずれかに整数を右詰めで表示する。
+// 負の数にはSetSignPlacementに従ってマイナス記号を付ける。
+// 収まらない場合はOverflowStrategyに従う。
+func (d *Device) WriteInt(display int, v int64) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	return d.writeInt(display, v)
+}
+
+// WriteInt16 is like WriteInt, but treats the two 8-digit displays as a
+// single 16-digit display.
+//
+// WriteInt16はWriteIntと同様だが、2つの8桁ディスプレイを1つの16桁
+// ディスプレイとして扱う。
+func (d *Device) WriteInt16(v int64) error {
+	return d.writeInt(combinedDisplay, v)
+}
+
+// writeInt is the common part of WriteInt and WriteInt16.
+//
+// writeIntは、WriteIntとWriteInt16の共通部分。
+func (d *Device) writeInt(display int, v int64) error {
+	if handled, err := d.writeOutOfRange(display, float64(v)); handled {
+		return err
+	}
+	text := strconv.FormatInt(v, 10)
+	if d.grouping {
+		text = groupThousands(text)
+	}
+	if numberWidth(text) > d.width(display) {
+		return d.writeOverflow(display, float64(v))
+	}
+	return d.writeNumber(display, text)
+}
+
+// WriteFixed displays a fixed-point number kept as a scaled integer,
+// right-aligned. The last decimals digits of value are shown after the
+// decimal point, so WriteFixed(0, 3300, 3) shows "3.300". It needs no
+// floating-point math, for microcontrollers without an FPU; only the
+// limits of SetRange, if one is set, are scaled in floating point once per
+// number of decimals. If the number does not fit, the OverflowStrategy
+// decides what is shown.
+//
+// WriteFixedは、整数に倍率を掛けて保持している固定小数点数を右詰めで表示
+// する。valueの下decimals桁を小数点以下として表示するので、
+// WriteFixed(0, 3300, 3)は"3.300"を表示する。浮動小数点の計算を使わない
+// ので、FPUのないマイコンに向いている。SetRangeで範囲を設定した場合だけ、
+// その範囲を小数の桁数ごとに一度浮動小数点で換算する。収まらない場合は
+// OverflowStrategyに従う。
+func (d *Device) WriteFixed(display int, value int64, decimals int) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if handled, err := d.writeFixedOutOfRange(display, value, decimals); handled {
+		return err
+	}
+	text := formatFixed(value, decimals)
+	if numberWidth(text) > d.digits {
+		return d.writeFixedOverflow(display, text)
+	}
+	return d.writeNumber(display, text)
+}
+
+// formatFixed formats value with its last decimals digits after the
+// decimal point.
+//
+// formatFixedは、valueの下decimals桁を小数点以下として整形する。
+func formatFixed(value int64, decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	sign := ""
+	abs := uint64(value)
+	if value < 0 {
+		sign = "-"
+		abs = -abs // Also correct for math.MinInt64
+	}
+	digits := strconv.FormatUint(abs, 10)
+	for len(digits) <= decimals {
+		digits = "0" + digits
+	}
+	if decimals == 0 {
+		return sign + digits
+	}
+	point := len(digits) - decimals
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// numberWidth returns the number of digits a formatted number takes up.
+// Decimal points share the digit before them.
+//
+// numberWidthは、整形した数値が使う桁数を返す。小数点は前の桁と共有する。
+func numberWidth(text string) int {
+	width := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '.' {
+			width++
+		}
+	}
+	return width
+}
+
+// writeNumber clears the display and writes a formatted number that is
+// known to fit, right-aligned, with the sign placed by SetSignPlacement.
+//
+// writeNumberは、ディスプレイをクリアし、収まることがわかっている整形済みの
+// 数値を右詰めで書き込む。符号はSetSignPlacementに従って置く。
+func (d *Device) writeNumber(display int, text string) error {
+	return d.writeNumberSuffix(display, text, nil)
+}
+
+// writeNumberSuffix is like writeNumber, but follows the number with suffix,
+// such as a unit.
+//
+// writeNumberSuffixはwriteNumberと同様だが、数値の後に単位などのsuffixを
+// 付ける。
+func (d *Device) writeNumberSuffix(display int, text string, suffix []cell) error {
+	cells, _, err := d.layoutString(text)
+	if err != nil {
+		return err
+	}
+	cells = append(cells, suffix...)
+	if text[0] == '-' && d.padding == PadZero {
+		cells = d.padCells(display, cells, 1)
+	} else if text[0] != '-' {
+		cells = d.padCells(display, cells, 0)
+	}
+	if d.sign == SignLeft && text[0] == '-' && len(cells) > 1 {
+		d.writeRightAligned(display, cells[1:])
+		d.placeOn(display, 0, cells[0])
+		return nil
+	}
+	d.writeRightAligned(display, cells)
+	return nil
+}
+
+// writeRightAligned clears the display and writes cells that are known to
+// fit, right-aligned.
+//
+// writeRightAlignedは、ディスプレイをクリアし、収まることがわかっている
+// セルを右詰めで書き込む。
+func (d *Device) writeRightAligned(display int, cells []cell) {
+	d.clearSurface(display)
+	start := d.width(display) - len(cells)
+	for i, c := range cells {
+		d.placeOn(display, start+i, c)
+	}
+	d.autoDisplay()
+}
+
+// Radix prefixes drawn with their own patterns, since the font has no
+// lowercase 'x' or 'o'.
+//
+// 基数の接頭辞。フォントに小文字の'x'や'o'がないので独自のパターンで描く。
+var (
+	prefixHex = []cell{{pattern: segA | segB | segC | segD | segE | segF}, {pattern: segF | segE | segG | segB | segC}} // "0x", x drawn like H
+	prefixBin = []cell{{pattern: segA | segB | segC | segD | segE | segF}, {pattern: segF | segE | segD | segC | segG}} // "0b"
+	prefixOct = []cell{{pattern: segA | segB | segC | segD | segE | segF}, {pattern: segC | segD | segE | segG}}        // "0o"
+)
+
+// hexDigits are the patterns of the digits 0-F of WriteHex, WriteBin and
+// WriteOct, drawn without the font so that hexadecimal also shows with a
+// font that has no letters, such as the one of the ht16k33_minfont build.
+//
+// hexDigitsは、WriteHex、WriteBin、WriteOctの数字0-Fのパターン。
+// ht16k33_minfontビルドのフォントのような英字のないフォントでも16進数を
+// 表示できるよう、フォントを使わずに描く。
+var hexDigits = [16]byte{
+	segA | segB | segC | segD | segE | segF,        // 0
+	segB | segC,                                    // 1
+	segA | segB | segG | segE | segD,               // 2
+	segA | segB | segG | segC | segD,               // 3
+	segF | segG | segB | segC,                      // 4
+	segA | segF | segG | segC | segD,               // 5
+	segA | segF | segE | segD | segC | segG,        // 6
+	segA | segB | segC,                             // 7
+	segA | segB | segC | segD | segE | segF | segG, // 8
+	segA | segB | segC | segD | segF | segG,        // 9
+	segA | segB | segC | segE | segF | segG,        // A
+	segF | segE | segD | segC | segG,               // b
+	segA | segF | segE | segD,                      // C
+	segB | segC | segD | segE | segG,               // d
+	segA | segF | segG | segE | segD,               // E
+	segA | segF | segG | segE,                      // F
+}
+
+// radixCells returns the cells of the digits of a number formatted by
+// strconv.FormatUint.
+//
+// radixCellsは、strconv.FormatUintで整形した数値の各桁のセルを返す。
+func radixCells(text string) []cell {
+	cells := make([]cell, 0, len(text))
+	for _, r := range text {
+		digit := r - '0'
+		if r >= 'a' {
+			digit = r - 'a' + 10
+		}
+		cells = append(cells, cell{pattern: hexDigits[digit]})
+	}
+	return cells
+}
+
+// WriteHex displays v in hexadecimal, right-aligned, for showing register
+// values. With prefix, it is preceded by "0x" (the x is drawn like an H).
+// If it does not fit, the display is left unchanged and ErrOverflow is
+// returned.
+//
+// WriteHexは、レジスタの値などを表示するため、vを16進数で右詰めに表示する。
+// prefixがtrueなら前に"0x"を付ける(xはHのように描かれる)。収まらない場合は
+// ディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) WriteHex(display int, v uint64, prefix bool) error {
+	return d.writeRadix(display, strconv.FormatUint(v, 16), prefix, prefixHex)
+}
+
+// WriteBin displays v in binary, right-aligned, for showing bitmasks.
+// With prefix, it is preceded by "0b".
+//
+// WriteBinは、ビットマスクなどを表示するため、vを2進数で右詰めに表示する。
+// prefixがtrueなら前に"0b"を付ける。
+func (d *Device) WriteBin(display int, v uint64, prefix bool) error {
+	return d.writeRadix(display, strconv.FormatUint(v, 2), prefix, prefixBin)
+}
+
+// WriteOct displays v in octal, right-aligned. With prefix, it is preceded
+// by "0o" (with a lowercase o).
+//
+// WriteOctは、vを8進数で右詰めに表示する。prefixがtrueなら前に"0o"(小文字の
+// o)を付ける。
+func (d *Device) WriteOct(display int, v uint64, prefix bool) error {
+	return d.writeRadix(display, strconv.FormatUint(v, 8), prefix, prefixOct)
+}
+
+// writeRadix is the common part of WriteHex, WriteBin and WriteOct.
+//
+// writeRadixは、WriteHex、WriteBin、WriteOctの共通部分。
+func (d *Device) writeRadix(display int, text string, prefix bool, prefixCells []cell) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	cells := radixCells(text)
+	if prefix {
+		cells = append(append([]cell(nil), prefixCells...), cells...)
+	}
+	if len(cells) > d.digits {
+		return ErrOverflow
+	}
+	if prefix {
+		cells = d.padCells(display, cells, len(prefixCells))
+	} else {
+		cells = d.padCells(display, cells, 0)
+	}
+	d.writeRightAligned(display, cells)
+	return nil
+}
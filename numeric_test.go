@@ -0,0 +1,417 @@
+package ht16k33
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+// assertDisplay checks that a display shows the same as WriteString(display, expected).
+func assertDisplay(t *testing.T, device *Device, display int, expected string) {
+	t.Helper()
+	assertDisplayFont(t, device, display, DefaultFont, expected)
+}
+
+// assertDisplayFont is like assertDisplay, but renders the expected string with font.
+func assertDisplayFont(t *testing.T, device *Device, display int, font Font, expected string) {
+	t.Helper()
+	expectedDevice, err := New(&mockI2C{}, 0x70, WithGeometry(device.displays, device.digits), WithFont(font))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+	expectedDevice.WriteString(display, expected)
+	rows := device.buffer[display*segmentRows : (display+1)*segmentRows]
+	expectedRows := expectedDevice.buffer[display*segmentRows : (display+1)*segmentRows]
+	if !bytes.Equal(rows, expectedRows) {
+		t.Errorf("FAIL: Display %d should show %q!\nExpected: %x\nGot:      %x", display, expected, expectedRows, rows)
+	}
+}
+
+// glyphFont has the patterns that are drawn without the font, and so also
+// in the ht16k33_minfont build: the hexadecimal digits, the radix
+// prefixes ("0X", also written "0H", and "0B") and the unit suffixes (the
+// percent sign as "ou").
+var glyphFont = fallbackFont{MapFont{
+	'A': hexDigits[10], 'B': hexDigits[11], 'C': hexDigits[12], 'D': hexDigits[13], 'E': hexDigits[14], 'F': hexDigits[15],
+	'X': prefixHex[1].pattern, 'H': prefixHex[1].pattern, '°': suffixCelsius[0].pattern,
+	'o': suffixPercent[0].pattern, 'u': suffixPercent[1].pattern, 'P': suffixPC[0].pattern,
+}}
+
+// TestWriteFloat verifies the precision reduction and right alignment.
+func TestWriteFloat(t *testing.T) {
+	testCases := []struct {
+		name     string
+		v        float64
+		prec     int
+		expected string
+	}{
+		{name: "Fits", v: 3.14159, prec: 3, expected: "    3.142"},
+		{name: "Negative", v: -2.5, prec: 2, expected: "    -2.50"},
+		{name: "Reduced precision", v: 123456.789, prec: 3, expected: "123456.79"},
+		{name: "No decimals left", v: 12345678.4, prec: 2, expected: "12345678"},
+		{name: "Negative precision", v: 7.6, prec: -1, expected: "       8"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := newTestDevice(t, &mockI2C{})
+			if err := device.WriteFloat(0, tc.v, tc.prec); err != nil {
+				t.Fatalf("WriteFloat() returned an unexpected error: %v", err)
+			}
+			assertDisplay(t, device, 0, tc.expected)
+		})
+	}
+
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "8")
+	for _, v := range []float64{123456789, math.NaN(), math.Inf(1)} {
+		if err := device.WriteFloat(0, v, 0); err != ErrOverflow {
+			t.Errorf("FAIL: WriteFloat(%v) should return ErrOverflow, got %v", v, err)
+		}
+	}
+	assertDisplay(t, device, 0, "8")
+}
+
+// TestWriteRadix verifies the hexadecimal, binary and octal writers.
+func TestWriteRadix(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	if err := device.WriteHex(0, 0xBEEF, false); err != nil {
+		t.Fatalf("WriteHex() returned an unexpected error: %v", err)
+	}
+	assertDisplayFont(t, device, 0, glyphFont, "    BEEF")
+
+	if err := device.WriteBin(1, 0b101, true); err != nil {
+		t.Fatalf("WriteBin() returned an unexpected error: %v", err)
+	}
+	assertDisplayFont(t, device, 1, glyphFont, "   0B101")
+
+	if err := device.WriteOct(0, 0755, false); err != nil {
+		t.Fatalf("WriteOct() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "     755")
+
+	if err := device.WriteHex(0, 0x1A, true); err != nil {
+		t.Fatalf("WriteHex() returned an unexpected error: %v", err)
+	}
+	assertDisplayFont(t, device, 0, glyphFont, "    0X1A")
+
+	if err := device.WriteBin(0, 0x1FF, false); err != ErrOverflow {
+		t.Errorf("FAIL: WriteBin() of 9 bits should return ErrOverflow, got %v", err)
+	}
+	if err := device.WriteHex(0, 0xFFFFFFF, true); err != ErrOverflow {
+		t.Errorf("FAIL: WriteHex() of 7 digits with a prefix should return ErrOverflow, got %v", err)
+	}
+	assertDisplayFont(t, device, 0, glyphFont, "    0X1A")
+}
+
+// TestWriteFixed verifies scaled integers with a decimal point.
+func TestWriteFixed(t *testing.T) {
+	testCases := []struct {
+		value    int64
+		decimals int
+		expected string
+	}{
+		{value: 3300, decimals: 3, expected: "    3.300"},
+		{value: 5, decimals: 2, expected: "     0.05"},
+		{value: -5, decimals: 2, expected: "    -0.05"},
+		{value: -1234, decimals: 0, expected: "   -1234"},
+		{value: 12345678, decimals: 4, expected: "1234.5678"},
+	}
+
+	for _, tc := range testCases {
+		device := newTestDevice(t, &mockI2C{})
+		if err := device.WriteFixed(0, tc.value, tc.decimals); err != nil {
+			t.Fatalf("WriteFixed(%d, %d) returned an unexpected error: %v", tc.value, tc.decimals, err)
+		}
+		assertDisplay(t, device, 0, tc.expected)
+	}
+
+	device := newTestDevice(t, &mockI2C{})
+	if err := device.WriteFixed(0, -12345678, 1); err != ErrOverflow {
+		t.Errorf("FAIL: WriteFixed() of 9 digits should return ErrOverflow, got %v", err)
+	}
+	if got := formatFixed(math.MinInt64, 18); got != "-9.223372036854775808" {
+		t.Errorf("FAIL: formatFixed(MinInt64, 18) = %q", got)
+	}
+}
+
+// TestOverflowScientific verifies the scientific notation fallback.
+func TestOverflowScientific(t *testing.T) {
+	testCases := []struct {
+		v        float64
+		expected string
+	}{
+		{v: 123456789, expected: "1.23457E8"},
+		{v: -123456789, expected: "-1.2346E8"},
+		{v: 1.5e300, expected: "  1.5E300"},
+		{v: -1.5e20, expected: "  -1.5E20"},
+	}
+
+	for _, tc := range testCases {
+		device := newTestDevice(t, &mockI2C{})
+		device.SetOverflowStrategy(OverflowScientific)
+		if err := device.WriteFloat(0, tc.v, 2); err != nil {
+			t.Fatalf("WriteFloat(%v) returned an unexpected error: %v", tc.v, err)
+		}
+		assertDisplay(t, device, 0, tc.expected)
+	}
+
+	device := newTestDevice(t, &mockI2C{})
+	device.SetOverflowStrategy(OverflowScientific)
+	if err := device.WriteFixed(1, 123456789, 0); err != nil {
+		t.Fatalf("WriteFixed() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 1, "1.23457E8")
+
+	// WriteFixed works on the digits, but shows what WriteFloat shows.
+	fixed := []struct {
+		value    int64
+		decimals int
+	}{
+		{123456789012, 2},
+		{-123456789012, 2},
+		{9999999996, 2},
+		{-9999999996, 2},
+		{100000000000, 3},
+		{math.MaxInt64, 0},
+	}
+	for _, tc := range fixed {
+		device.WriteFixed(0, tc.value, tc.decimals)
+		device.WriteFloat(1, float64(tc.value)/math.Pow10(tc.decimals), 2)
+		if got, expected := device.buffer[:segmentRows], device.buffer[segmentRows:]; string(got) != string(expected) {
+			t.Errorf("FAIL: WriteFixed(%d, %d) differs from WriteFloat!\nExpected: %x\nGot:      %x", tc.value, tc.decimals, expected, got)
+		}
+	}
+}
+
+// TestWriteInt verifies integers, sign placement and the overflow strategies.
+func TestWriteInt(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	if err := device.WriteInt(0, -42); err != nil {
+		t.Fatalf("WriteInt() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "     -42")
+
+	device.SetSignPlacement(SignLeft)
+	device.WriteInt(0, -42)
+	assertDisplay(t, device, 0, "-     42")
+
+	if err := device.WriteInt(0, -12345678); err != ErrOverflow {
+		t.Errorf("FAIL: WriteInt(-12345678) should return ErrOverflow, got %v", err)
+	}
+	assertDisplay(t, device, 0, "-     42")
+
+	device.SetOverflowStrategy(OverflowClamp)
+	device.WriteInt(0, -12345678)
+	assertDisplay(t, device, 0, "-9999999")
+	device.WriteInt(1, 123456789)
+	assertDisplay(t, device, 1, "99999999")
+
+	if err := device.WriteInt(2, 1); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: WriteInt() on display 2 should return ErrInvalidDisplay, got %v", err)
+	}
+}
+
+// TestWriteInt16 verifies integers across both displays.
+func TestWriteInt16(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	if err := device.WriteInt16(-1234567890); err != nil {
+		t.Fatalf("WriteInt16() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "     -12")
+	assertDisplay(t, device, 1, "34567890")
+
+	if err := device.WriteInt16(math.MinInt64); err != ErrOverflow {
+		t.Errorf("FAIL: WriteInt16(MinInt64) should return ErrOverflow, got %v", err)
+	}
+}
+
+// TestNegativeZero verifies that values rounded to zero have no sign.
+func TestNegativeZero(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteFloat(0, -0.001, 2)
+	assertDisplay(t, device, 0, "     0.00")
+}
+
+// TestSetPadding verifies zero padding of numbers.
+func TestSetPadding(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetPadding(PadZero)
+
+	device.WriteInt(0, 42)
+	assertDisplay(t, device, 0, "00000042")
+	device.WriteInt(0, -42)
+	assertDisplay(t, device, 0, "-0000042")
+	device.WriteFloat(1, 3.5, 1)
+	assertDisplay(t, device, 1, "0000003.5")
+	device.WriteHex(1, 0xFF, true)
+	assertDisplayFont(t, device, 1, glyphFont, "0H0000FF")
+
+	device.SetPadding(PadBlank)
+	device.WriteInt(0, 42)
+	assertDisplay(t, device, 0, "      42")
+}
+
+// TestSetGrouping verifies thousands separators on integers.
+func TestSetGrouping(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetGrouping(true)
+
+	device.WriteInt(0, 1234567)
+	assertDisplay(t, device, 0, " 1.234.567")
+	device.WriteInt(0, -1234)
+	assertDisplay(t, device, 0, "   -1.234")
+	device.WriteInt(0, 999)
+	assertDisplay(t, device, 0, "     999")
+}
+
+// TestOverflowIndicator verifies the default and custom overflow indicators.
+func TestOverflowIndicator(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetOverflowStrategy(OverflowIndicator)
+
+	if err := device.WriteInt(0, 123456789); err != nil {
+		t.Fatalf("WriteInt() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "--------")
+
+	device.SetOverflowIndicator("OF")
+	device.WriteFloat(1, 1e12, 2)
+	assertDisplay(t, device, 1, "      OF")
+
+	device.SetOverflowIndicator("")
+	device.WriteInt16(math.MaxInt64)
+	assertDisplay(t, device, 0, "--------")
+	assertDisplay(t, device, 1, "--------")
+}
+
+// TestNotationSI verifies the SI prefixes of WriteFloat.
+func TestNotationSI(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetNotation(NotationSI)
+	prefixes := MapFont{'k': segA | segC | segE | segF | segG, 'M': segA | segB | segC | segE | segF,
+		'm': segA | segC | segE | segG, 'u': segB | segE | segF | segG}
+
+	tests := []struct {
+		v        float64
+		prec     int
+		expected string
+	}{
+		{4700, 1, "     4.7k"},
+		{12.5e6, 2, "   12.50M"},
+		{-0.0033, 1, "    -3.3m"},
+		{22e-6, 0, "     22u"},
+		{12, 1, "     12.0"},
+		{999999, 2, "    1.00M"},
+		{-999.9996, 2, "   -1.00k"},
+		{0.9999996, 2, "     1.00"},
+	}
+	for _, test := range tests {
+		if err := device.WriteFloat(0, test.v, test.prec); err != nil {
+			t.Fatalf("WriteFloat(%v) returned an unexpected error: %v", test.v, err)
+		}
+		expected := newTestDevice(t, &mockI2C{})
+		expected.SetFont(fallbackFont{prefixes})
+		expected.WriteString(0, test.expected)
+		if device.buffer != expected.buffer {
+			t.Errorf("FAIL: WriteFloat(%v) is wrong!\nExpected: %x\nGot:      %x", test.v, expected.buffer[:], device.buffer[:])
+		}
+	}
+}
+
+// fallbackFont looks runes up in a MapFont first and then in DefaultFont.
+type fallbackFont struct {
+	MapFont
+}
+
+func (f fallbackFont) Glyph(r rune) (byte, bool) {
+	if pattern, ok := f.MapFont.Glyph(r); ok {
+		return pattern, true
+	}
+	return DefaultFont.Glyph(r)
+}
+
+// TestFormatRounded verifies the rounding modes.
+func TestFormatRounded(t *testing.T) {
+	tests := []struct {
+		v        float64
+		prec     int
+		mode     RoundingMode
+		expected string
+	}{
+		{2.675, 2, RoundNearest, "2.67"},
+		{2.675, 2, RoundHalfUp, "2.68"},
+		{-2.5, 0, RoundHalfUp, "-3"},
+		{2.5, 0, RoundHalfEven, "2"},
+		{3.5, 0, RoundHalfEven, "4"},
+		{2.51, 0, RoundHalfEven, "3"},
+		{9.99, 1, RoundHalfUp, "10.0"},
+		{9.99, 1, RoundTruncate, "9.9"},
+		{-9.99, 1, RoundTruncate, "-9.9"},
+		{12, 2, RoundTruncate, "12.00"},
+	}
+	for _, test := range tests {
+		if got := formatRounded(test.v, test.prec, test.mode); got != test.expected {
+			t.Errorf("FAIL: formatRounded(%v, %d, %d) is wrong!\nExpected: %s\nGot:      %s", test.v, test.prec, test.mode, test.expected, got)
+		}
+	}
+}
+
+// TestSetRounding verifies that WriteFloat uses the rounding mode.
+func TestSetRounding(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetRounding(RoundTruncate)
+	device.WriteFloat(0, 1.999, 2)
+	assertDisplay(t, device, 0, "     1.99")
+}
+
+// TestSetRange verifies the out-of-range indicators.
+func TestSetRange(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetRange(-10, 100)
+
+	device.WriteFloat(0, 50.5, 1)
+	assertDisplay(t, device, 0, "     50.5")
+	device.WriteFloat(0, 100.1, 1)
+	assertDisplay(t, device, 0, "      HI")
+	device.WriteInt(1, -11)
+	assertDisplay(t, device, 1, "      LO")
+	device.WriteFixed(1, 1001, 1)
+	assertDisplay(t, device, 1, "      HI")
+	if err := device.WriteFloat(0, math.Inf(-1), 1); err != nil {
+		t.Errorf("FAIL: WriteFloat(-Inf) should show LO, got %v", err)
+	}
+	assertDisplay(t, device, 0, "      LO")
+
+	device.SetRangeIndicators("Under", "")
+	device.WriteInt(0, -20)
+	assertDisplay(t, device, 0, "   Under")
+
+	device.SetRange(1, 0)
+	device.WriteInt(0, -20)
+	assertDisplay(t, device, 0, "     -20")
+
+	// WriteFixed compares with the limits scaled to its decimals.
+	device.SetRangeIndicators("", "")
+	device.SetRange(-0.5, 2.5)
+	fixed := []struct {
+		value    int64
+		decimals int
+		expected string
+	}{
+		{250, 2, "     2.50"},
+		{251, 2, "      HI"},
+		{-50, 2, "    -0.50"},
+		{-51, 2, "      LO"},
+		{25, 1, "      2.5"},
+		{26, 1, "      HI"},
+		{3, 0, "      HI"},
+		{-1, 0, "      LO"},
+	}
+	for _, tc := range fixed {
+		device.WriteFixed(1, tc.value, tc.decimals)
+		assertDisplay(t, device, 1, tc.expected)
+	}
+}
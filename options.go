@@ -0,0 +1,67 @@
+package ht16k33
+
+// Option customizes a Device created by New.
+//
+// Optionは、Newで作るDeviceをカスタマイズする。
+type Option func(*Device)
+
+// WithInitialBrightness sets the brightness (0-15) applied by Configure.
+// Values above 15 are clamped to 15.
+//
+// WithInitialBrightnessは、Configureで設定される明るさ(0-15)を指定する。
+// 15より大きい値は15に丸められる。
+func WithInitialBrightness(brightness uint8) Option {
+	return func(d *Device) {
+		if brightness > 15 {
+			brightness = 15
+		}
+		d.currentBrightness = brightness
+	}
+}
+
+// WithFont replaces the font used to render characters.
+// A nil font falls back to DefaultFont.
+//
+// WithFontは、文字の描画に使うフォントを差し替える。
+// nilを渡すとDefaultFontが使われる。
+func WithFont(font Font) Option {
+	return func(d *Device) {
+		d.font = font
+	}
+}
+
+// WithGeometry describes how the displays are wired: the number of display
+// units (1-2) and the number of digits on each of them (1-8). New returns
+// ErrInvalidGeometry for anything the HT16K33 cannot drive.
+//
+// WithGeometryは、ディスプレイの結線構成を指定する。ディスプレイの数(1-2)と、
+// それぞれの桁数(1-8)。HT16K33で駆動できない構成の場合、Newは
+// ErrInvalidGeometryを返す。
+func WithGeometry(displays, digitsPerDisplay int) Option {
+	return func(d *Device) {
+		d.displays = displays
+		d.digits = digitsPerDisplay
+	}
+}
+
+// WithAutoFlush makes every method that changes the buffer transfer it to
+// the LED driver right away, so Display does not have to be called.
+//
+// WithAutoFlushをtrueにすると、バッファを変更するメソッドがすぐにLEDドライバ
+// へ転送するので、Displayを呼ぶ必要がなくなる。
+func WithAutoFlush(enabled bool) Option {
+	return func(d *Device) {
+		d.autoFlush = enabled
+	}
+}
+
+// WithClock sets the Clock that the non-blocking effects read the time
+// from, instead of time.Now. A nil clock means time.Now.
+//
+// WithClockは、ノンブロッキングの効果がtime.Nowの代わりに時刻を読む
+// Clockを設定する。nilならtime.Nowになる。
+func WithClock(clock Clock) Option {
+	return func(d *Device) {
+		d.clock = clock
+	}
+}
@@ -0,0 +1,82 @@
+package ht16k33
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestWithInitialBrightness verifies that Configure applies the initial brightness.
+func TestWithInitialBrightness(t *testing.T) {
+	mockBus := &mockI2C{}
+	device, err := New(mockBus, 0x70, WithInitialBrightness(7))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+
+	if err := device.Configure(Config{}); err != nil {
+		t.Fatalf("Configure() returned an unexpected error: %v", err)
+	}
+
+	expected := []byte{ht16k33SetBrightness | 7}
+	if !bytes.Equal(mockBus.data, expected) {
+		t.Errorf("FAIL: Last command after Configure is wrong!\nExpected: %x\nGot:      %x", expected, mockBus.data)
+	}
+}
+
+// TestWithFont verifies that a custom font is used for rendering.
+func TestWithFont(t *testing.T) {
+	mockBus := &mockI2C{}
+	device, err := New(mockBus, 0x70, WithFont(MapFont{'x': segA}))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+
+	device.SetDigitOnDisplay(0, 0, 'x', false)
+	device.SetDigitOnDisplay(0, 1, '8', false) // Not in the custom font
+
+	expectedBuffer := [16]byte{1 << 0}
+	if !bytes.Equal(device.buffer[:], expectedBuffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedBuffer[:], device.buffer[:])
+	}
+}
+
+// TestWithGeometry verifies that the geometry is validated and limits the digits.
+func TestWithGeometry(t *testing.T) {
+	for _, geometry := range [][2]int{{0, 8}, {3, 8}, {2, 0}, {2, 9}} {
+		if _, err := New(&mockI2C{}, 0x70, WithGeometry(geometry[0], geometry[1])); err != ErrInvalidGeometry {
+			t.Errorf("FAIL: New() with geometry %v should return ErrInvalidGeometry, got %v", geometry, err)
+		}
+	}
+
+	mockBus := &mockI2C{}
+	device, err := New(mockBus, 0x70, WithGeometry(2, 4))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+
+	// Position 4 is the first digit of display B with 4-digit displays.
+	device.SetDigit16(4, '1', false)
+	// Position 8 is out of range and must be ignored.
+	device.SetDigit16(8, '1', false)
+
+	expectedBuffer := [16]byte{9: 1 << 0, 10: 1 << 0}
+	if !bytes.Equal(device.buffer[:], expectedBuffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedBuffer[:], device.buffer[:])
+	}
+}
+
+// TestWithAutoFlush verifies that buffer changes are sent without calling Display.
+func TestWithAutoFlush(t *testing.T) {
+	mockBus := &mockI2C{}
+	device, err := New(mockBus, 0x70, WithAutoFlush(true))
+	if err != nil {
+		t.Fatalf("New() returned an unexpected error: %v", err)
+	}
+
+	device.WriteString(0, "1")
+
+	expectedI2CData := append([]byte{0x00}, device.buffer[:]...)
+	if !bytes.Equal(mockBus.data, expectedI2CData) {
+		t.Errorf("FAIL: Data sent after WriteString is wrong!\nExpected: %x\nGot:      %x", expectedI2CData, mockBus.data)
+	}
+}
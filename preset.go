@@ -0,0 +1,95 @@
+package ht16k33
+
+import "time"
+
+// PresetOff is the preset level that blanks the displays instead of setting
+// a brightness.
+//
+// PresetOffは、明るさを設定する代わりにディスプレイを消すプリセットの値。
+const PresetOff = 0xFF
+
+// preset is a named brightness set with SetPreset.
+type preset struct {
+	name  string
+	level uint8
+}
+
+// defaultPresets are the presets available until SetPreset changes them.
+var defaultPresets = [...]preset{
+	{"day", 15},
+	{"night", 1},
+	{"off", PresetOff},
+}
+
+// SetPreset stores a named brightness (0-15, or PresetOff) on the Device,
+// so that the modules of an application can share consistent levels with
+// ApplyPreset. The presets "day" (15), "night" (1) and "off" (PresetOff)
+// exist by default and can be changed.
+//
+// SetPresetは、名前付きの明るさ(0-15、またはPresetOff)をDeviceに保存し、
+// アプリケーションの各モジュールがApplyPresetで一貫したレベルを共有できる
+// ようにする。"day"(15)、"night"(1)、"off"(PresetOff)は最初から存在し、
+// 変更できる。
+func (d *Device) SetPreset(name string, brightness uint8) {
+	if brightness != PresetOff {
+		brightness = min(brightness, 15)
+	}
+	if d.presets == nil {
+		d.presets = append(d.presets, defaultPresets[:]...)
+	}
+	for i := range d.presets {
+		if d.presets[i].name == name {
+			d.presets[i].level = brightness
+			return
+		}
+	}
+	d.presets = append(d.presets, preset{name, brightness})
+}
+
+// ApplyPreset applies the named preset. With a duration above 0 the
+// brightness changes smoothly like SetBrightnessSmooth, otherwise at once.
+// PresetOff blanks the displays at once, keeping their content, and the
+// next preset shows them again. It returns ErrUnknownPreset for a name that
+// does not exist.
+//
+// ApplyPresetは、名前付きのプリセットを適用する。durationが0より大きいと
+// SetBrightnessSmoothのように明るさを滑らかに変え、そうでなければすぐに
+// 変える。PresetOffはディスプレイの内容を残したまますぐに消し、次の
+// プリセットで再び表示する。存在しない名前にはErrUnknownPresetを返す。
+func (d *Device) ApplyPreset(name string, duration time.Duration) error {
+	presets := d.presets
+	if presets == nil {
+		presets = defaultPresets[:]
+	}
+	found := false
+	var brightness uint8
+	for _, p := range presets {
+		if p.name == name {
+			brightness, found = p.level, true
+			break
+		}
+	}
+	if !found {
+		return ErrUnknownPreset
+	}
+	if brightness == PresetOff {
+		if !d.presetOff {
+			d.presetOff = true
+			d.Display()
+		}
+		return nil
+	}
+	if d.presetOff {
+		if duration > 0 {
+			d.SetBrightness(0)
+		}
+		d.presetOff = false
+		d.Display()
+	}
+	if duration > 0 {
+		d.SetBrightnessSmooth(brightness, duration)
+	} else {
+		d.SetBrightness(brightness)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestApplyPreset verifies the default and stored presets, including the blanking of PresetOff.
+func TestApplyPreset(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.WriteString(0, "12")
+
+	if err := device.ApplyPreset("night", 0); err != nil || device.GetBrightness() != 1 {
+		t.Fatalf("FAIL: ApplyPreset(night) should set 1, got %d (%v)", device.GetBrightness(), err)
+	}
+	device.SetPreset("reading", 9)
+	if device.ApplyPreset("reading", 0); device.GetBrightness() != 9 {
+		t.Errorf("FAIL: ApplyPreset() is wrong!\nExpected: %d\nGot:      %d", 9, device.GetBrightness())
+	}
+	if err := device.ApplyPreset("dusk", 0); err != ErrUnknownPreset {
+		t.Errorf("FAIL: ApplyPreset() for an unknown name should return ErrUnknownPreset, got %v", err)
+	}
+
+	device.ApplyPreset("off", 0)
+	if mockBus.ram != [16]byte{} || device.GetBrightness() != 9 {
+		t.Fatalf("FAIL: ApplyPreset(off) should blank the displays, got %x", mockBus.ram)
+	}
+
+	// A smooth preset shows the content again and ramps up from 0.
+	device.ApplyPreset("day", time.Second)
+	if mockBus.ram != device.buffer || device.GetBrightness() != 0 || !device.IsAnimating() {
+		t.Fatalf("FAIL: ApplyPreset(day) should show the content and start at 0, got %d", device.GetBrightness())
+	}
+	device.StopAnimation()
+	if got := device.GetBrightness(); got != 15 {
+		t.Errorf("FAIL: ApplyPreset(day) should end at 15, got %d", got)
+	}
+}
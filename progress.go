@@ -0,0 +1,57 @@
+package ht16k33
+
+import "math"
+
+// progressLevels are the patterns of a digit of a progress bar as it
+// fills: the bottom, then the middle, then the top segment.
+//
+// progressLevelsは、進捗バーの桁が埋まっていくときのパターン。下、次に
+// 中央、次に上のセグメントを点灯する。
+var progressLevels = [...]byte{
+	segD,
+	segD | segG,
+	segD | segG | segA,
+}
+
+// WriteProgress draws a progress bar for fraction (0 to 1) across all
+// digits of a display, filling each digit from the left with its bottom,
+// middle and top segments in turn, so 8 digits have 24 steps. fraction is
+// rounded down to a step, so the bar is only full at 1, and values outside
+// 0 to 1 are clamped.
+//
+// WriteProgressは、fraction(0から1)の進捗バーをディスプレイのすべての桁に
+// 描く。左から各桁を下、中央、上のセグメントの順に埋めるので、8桁なら24段階
+// になる。fractionは段階に切り捨てるので、バーは1のときだけ満杯になり、
+// 0から1の範囲外の値は制限する。
+func (d *Device) WriteProgress(display int, fraction float64) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	d.clearDisplay(display)
+	d.drawBar(display, d.progressSteps(fraction))
+	d.autoDisplay()
+	return nil
+}
+
+// progressSteps returns how many steps of a bar across a display fraction
+// fills, rounded down and limited to the bar.
+//
+// progressStepsは、ディスプレイいっぱいのバーでfractionが埋める段階の数を
+// 切り捨てて、バーの範囲に制限して返す。
+func (d *Device) progressSteps(fraction float64) int {
+	if !(fraction > 0) {
+		return 0
+	}
+	return int(math.Min(fraction, 1) * float64(d.digits*len(progressLevels)))
+}
+
+// drawBar draws the first steps of a bar from the left of a display.
+//
+// drawBarは、ディスプレイの左からバーの最初のsteps段階を描く。
+func (d *Device) drawBar(display int, steps int) {
+	levels := len(progressLevels)
+	for pos := 0; pos < d.digits && steps > 0; pos++ {
+		d.setPattern(display, pos, progressLevels[min(steps, levels)-1], false)
+		steps -= levels
+	}
+}
@@ -0,0 +1,40 @@
+package ht16k33
+
+import (
+	"math"
+	"testing"
+)
+
+// TestWriteProgress verifies the steps of the bar, including partly filled digits.
+func TestWriteProgress(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		expected [8]byte
+	}{
+		{0, [8]byte{}},
+		{1.0 / 24, [8]byte{segD}},
+		{0.5, [8]byte{segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA}},
+		{14.0 / 24, [8]byte{segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG}},
+		{0.999, [8]byte{segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG}},
+		{2, [8]byte{segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA, segD | segG | segA}},
+		{-1, [8]byte{}},
+		{math.NaN(), [8]byte{}},
+	}
+	device := newTestDevice(t, &mockI2C{})
+	for _, tt := range tests {
+		if err := device.WriteProgress(0, tt.fraction); err != nil {
+			t.Fatalf("WriteProgress(%v) returned an unexpected error: %v", tt.fraction, err)
+		}
+		var got [8]byte
+		for pos := range got {
+			got[pos] = device.digitPattern(0, pos)
+		}
+		if got != tt.expected {
+			t.Errorf("FAIL: WriteProgress(%v) is wrong!\nExpected: %x\nGot:      %x", tt.fraction, tt.expected, got)
+		}
+	}
+
+	if err := device.WriteProgress(2, 0.5); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: WriteProgress() with an invalid display should return ErrInvalidDisplay, got %v", err)
+	}
+}
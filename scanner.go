@@ -0,0 +1,153 @@
+package ht16k33
+
+import "time"
+
+// scannerLevels are the patterns of the block of a scanner and its tail,
+// from the head back. Fewer lit segments look dimmer.
+//
+// scannerLevelsは、スキャナーのブロックとその尾のパターンで、頭から後ろへ
+// 並べたもの。点灯するセグメントが少ないほど暗く見える。
+var scannerLevels = [...]byte{
+	segA | segB | segC | segD | segE | segF | segG,
+	segB | segC | segE | segF,
+	segG,
+}
+
+// scannerAnimation is the Animation started by StartScanner and
+// StartScanner16.
+//
+// scannerAnimationは、StartScannerとStartScanner16で始めるAnimation。
+type scannerAnimation struct {
+	display  int
+	interval time.Duration
+	last     uint32
+	// trail has the positions of the head and the tail, from the head back.
+	// trailは、頭と尾の位置を頭から後ろへ持つ。
+	trail   [len(scannerLevels)]int
+	step    int
+	saved   [16]byte
+	stopped bool
+}
+
+// Start draws the block at the left end.
+func (s *scannerAnimation) Start(d *Device, now time.Time) {
+	s.saved = d.buffer
+	s.last = tickOf(now)
+	s.draw(d)
+	d.Display()
+}
+
+// Step moves the block by one digit per interval, turning at the ends.
+func (s *scannerAnimation) Step(d *Device, now time.Time) bool {
+	if s.stopped || tickDuration(tickOf(now)-s.last) < s.interval {
+		return false
+	}
+	s.last = tickOf(now)
+	width := d.width(s.display)
+	s.step = (s.step + 1) % max(2*(width-1), 1)
+	copy(s.trail[1:], s.trail[:])
+	s.trail[0] = s.step
+	if s.step >= width {
+		// On the way back.
+		s.trail[0] = 2*(width-1) - s.step
+	}
+	s.draw(d)
+	return true
+}
+
+// Done reports whether the scanner was stopped.
+func (s *scannerAnimation) Done() bool {
+	return s.stopped
+}
+
+// Stop restores the content under the scanner like StopScanner, without
+// sending it.
+func (s *scannerAnimation) Stop(d *Device) {
+	d.stopScanner(s.display)
+}
+
+// draw clears the display and draws the tail, then the head over it.
+//
+// drawは、ディスプレイをクリアし、尾を描いてからその上に頭を描く。
+func (s *scannerAnimation) draw(d *Device) {
+	d.clearSurface(s.display)
+	for i := len(s.trail) - 1; i >= 0; i-- {
+		d.setSurfacePattern(s.display, s.trail[i], scannerLevels[i])
+	}
+}
+
+// StartScanner shows a scanner on a display, like the lights of a certain
+// talking car: a lit block sweeps back and forth across the digits, one
+// digit per interval, with a tail of dimmer digits drawn with fewer
+// segments. The content of the display is shown again by StopScanner. It
+// is non-blocking: call Update from the main loop.
+//
+// StartScannerは、ある喋る車のライトのようなスキャナーをディスプレイに
+// 表示する。点灯したブロックがintervalごとに1桁ずつ桁の上を往復し、その後ろ
+// にセグメントを減らして暗く見せた尾が続く。ディスプレイの内容は
+// StopScannerで再び表示する。ノンブロッキングなので、メインループから
+// Updateを呼び出す。
+func (d *Device) StartScanner(display int, interval time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	d.startScanner(display, interval)
+	return nil
+}
+
+// StartScanner16 is like StartScanner, but sweeps across both displays as
+// one 16-digit strip. StopScanner on either display stops it.
+//
+// StartScanner16はStartScannerと同様だが、両方のディスプレイを1本の
+// 16桁の帯として往復する。どちらのディスプレイのStopScannerでも止まる。
+func (d *Device) StartScanner16(interval time.Duration) {
+	d.startScanner(combinedDisplay, interval)
+}
+
+// startScanner is the common part of StartScanner and StartScanner16.
+//
+// startScannerは、StartScannerとStartScanner16の共通部分。
+func (d *Device) startScanner(display int, interval time.Duration) {
+	slot := display
+	if display == combinedDisplay {
+		d.StopScanner(1)
+		slot = 0
+	}
+	d.StopScanner(slot)
+	d.scanners[slot] = &scannerAnimation{display: display, interval: interval}
+	d.Animate(d.scanners[slot])
+}
+
+// StopScanner stops the scanner on a display and shows the content it had
+// before the scanner.
+//
+// StopScannerは、ディスプレイのスキャナーを止め、スキャナーの前の内容を
+// 表示する。
+func (d *Device) StopScanner(display int) {
+	if d.stopScanner(display) {
+		d.Display()
+	}
+}
+
+// stopScanner is StopScanner without sending the buffer. It returns true
+// if a scanner was stopped.
+//
+// stopScannerは、バッファを送らないStopScanner。スキャナーを止めた場合は
+// trueを返す。
+func (d *Device) stopScanner(display int) bool {
+	stopped := false
+	for i, scanner := range d.scanners {
+		if scanner == nil || (scanner.display != display && scanner.display != combinedDisplay) {
+			continue
+		}
+		scanner.stopped = true
+		d.scanners[i] = nil
+		for row := range d.buffer {
+			if scanner.display == combinedDisplay || row/segmentRows == scanner.display {
+				d.buffer[row] = scanner.saved[row]
+			}
+		}
+		stopped = true
+	}
+	return stopped
+}
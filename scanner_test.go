@@ -0,0 +1,60 @@
+package ht16k33
+
+import "testing"
+
+// scannerFrame returns the pattern of each digit of display A.
+func scannerFrame(device *Device) [8]byte {
+	var frame [8]byte
+	for pos := range frame {
+		frame[pos] = device.digitPattern(0, pos)
+	}
+	return frame
+}
+
+// TestStartScanner verifies that the block sweeps with its tail and turns at the ends.
+func TestStartScanner(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "12")
+
+	if err := device.StartScanner(0, 0); err != nil {
+		t.Fatalf("StartScanner() returned an unexpected error: %v", err)
+	}
+	device.Update()
+	device.Update()
+	if got, want := scannerFrame(device), [8]byte{scannerLevels[2], scannerLevels[1], scannerLevels[0]}; got != want {
+		t.Errorf("FAIL: Block should have a tail!\nExpected: %x\nGot:      %x", want, got)
+	}
+
+	// Reach the right end, then turn back over the tail.
+	for i := 0; i < 5; i++ {
+		device.Update()
+	}
+	device.Update()
+	want := [8]byte{6: scannerLevels[0], 7: scannerLevels[1]}
+	if got := scannerFrame(device); got != want {
+		t.Errorf("FAIL: Block should turn at the right end!\nExpected: %x\nGot:      %x", want, got)
+	}
+
+	device.StopScanner(0)
+	assertDisplay(t, device, 0, "12")
+	if device.Update() {
+		t.Errorf("FAIL: Scanner should stop")
+	}
+}
+
+// TestStartScanner16 verifies that the block crosses into display B.
+func TestStartScanner16(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	device.StartScanner16(0)
+	for i := 0; i < 8; i++ {
+		device.Update()
+	}
+	if got := device.digitPattern(1, 0); got != scannerLevels[0] {
+		t.Errorf("FAIL: Block should be on the first digit of display B, got %x", got)
+	}
+	device.StopScanner(1)
+	if device.Update() {
+		t.Errorf("FAIL: StopScanner() on either display should stop a combined scanner")
+	}
+}
@@ -0,0 +1,100 @@
+package ht16k33
+
+import "time"
+
+// scheduleRamp is how long the brightness takes to change at the boundary
+// of a dimming period.
+const scheduleRamp = 2 * time.Second
+
+// dimmingPeriod is a time range registered with AddDimmingPeriod.
+//
+// dimmingPeriodは、AddDimmingPeriodで登録した時間帯。
+type dimmingPeriod struct {
+	start, end time.Duration
+	brightness uint8
+}
+
+// contains reports whether the time of day falls in the period, which may
+// wrap around midnight.
+func (p dimmingPeriod) contains(timeOfDay time.Duration) bool {
+	if p.start <= p.end {
+		return timeOfDay >= p.start && timeOfDay < p.end
+	}
+	return timeOfDay >= p.start || timeOfDay < p.end
+}
+
+// AddDimmingPeriod registers a time range, given as times of day from
+// midnight, in which the display is shown at brightness (0-15), for example
+// from 22h to 7h for a bedside clock. A range with end before start wraps
+// around midnight. When periods overlap, the one added first is used.
+// Outside every period, the brightness from before the first one is
+// restored. The brightness changes smoothly at the boundaries from Update
+// (or UpdateSchedule). The time of day is read from the Clock of the Device
+// (see WithClock) in its location, so a Clock that reads an RTC module
+// keeps the schedule on time.
+//
+// AddDimmingPeriodは、午前0時からの時刻で表す時間帯を登録し、その間は
+// ディスプレイを明るさbrightness(0-15)で表示する。たとえば枕元の時計なら
+// 22時から7時まで。endがstartより前の時間帯は午前0時をまたぐ。時間帯が
+// 重なる場合は、先に追加したものを使う。どの時間帯でもないときは、最初の
+// 時間帯の前の明るさに戻す。境界ではUpdate(またはUpdateSchedule)から明るさを
+// 滑らかに変える。時刻はDeviceのClock(WithClockを参照)からそのロケーション
+// で読むので、RTCモジュールを読むClockならスケジュールが正確に保たれる。
+func (d *Device) AddDimmingPeriod(start, end time.Duration, brightness uint8) error {
+	if start < 0 || start > 24*time.Hour || end < 0 || end > 24*time.Hour {
+		return ErrInvalidPeriod
+	}
+	d.schedule = append(d.schedule, dimmingPeriod{start, end, min(brightness, 15)})
+	return nil
+}
+
+// ClearDimmingSchedule removes all dimming periods, restoring the
+// brightness from before them if one is in effect.
+//
+// ClearDimmingScheduleは、すべての減光の時間帯を削除し、いずれかが有効なら
+// その前の明るさに戻す。
+func (d *Device) ClearDimmingSchedule() {
+	if d.schedulePeriod >= 0 {
+		if d.ramp != nil {
+			d.ramp.done = true
+		}
+		d.SetBrightness(d.scheduleRestore)
+	}
+	d.schedule = nil
+	d.schedulePeriod = -1
+}
+
+// UpdateSchedule checks the dimming schedule against the Clock and starts
+// the change of brightness when a boundary has been crossed. It returns
+// true when it has started one.
+//
+// UpdateScheduleは、減光スケジュールをClockと照らし合わせ、境界を越えた
+// ときに明るさの変化を始める。変化を始めたときはtrueを返す。
+func (d *Device) UpdateSchedule() bool {
+	if len(d.schedule) == 0 {
+		return false
+	}
+	now := d.now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	timeOfDay := now.Sub(midnight)
+	period := -1
+	for i, p := range d.schedule {
+		if p.contains(timeOfDay) {
+			period = i
+			break
+		}
+	}
+	if period == d.schedulePeriod {
+		return false
+	}
+	if d.schedulePeriod < 0 {
+		d.scheduleRestore = d.currentBrightness
+	}
+	d.schedulePeriod = period
+	target := d.scheduleRestore
+	if period >= 0 {
+		target = d.schedule[period].brightness
+	}
+	d.SetBrightnessSmooth(target, scheduleRamp)
+	return true
+}
@@ -0,0 +1,48 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDimmingSchedule verifies that the brightness follows the periods, including one across midnight.
+func TestDimmingSchedule(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetBrightness(10)
+	clock := device.clock.(*fakeClock)
+	clock.now = time.Date(2024, 1, 1, 21, 0, 0, 0, time.UTC)
+
+	if err := device.AddDimmingPeriod(22*time.Hour, 7*time.Hour, 1); err != nil {
+		t.Fatalf("AddDimmingPeriod() returned an unexpected error: %v", err)
+	}
+	if err := device.AddDimmingPeriod(0, 25*time.Hour, 1); err != ErrInvalidPeriod {
+		t.Errorf("FAIL: AddDimmingPeriod() to 25h should return ErrInvalidPeriod, got %v", err)
+	}
+
+	expected := []struct {
+		at         time.Time
+		brightness uint8
+	}{
+		{time.Date(2024, 1, 1, 21, 0, 0, 0, time.UTC), 10},
+		{time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC), 1},
+		{time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC), 1},
+		{time.Date(2024, 1, 2, 7, 0, 0, 0, time.UTC), 10},
+	}
+	for _, tt := range expected {
+		clock.now = tt.at
+		device.UpdateSchedule()
+		device.StopAnimation()
+		if got := device.GetBrightness(); got != tt.brightness {
+			t.Errorf("FAIL: Brightness at %v is wrong!\nExpected: %d\nGot:      %d", tt.at, tt.brightness, got)
+		}
+	}
+
+	// Clearing the schedule in a period restores the brightness.
+	clock.now = time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC)
+	device.Update()
+	device.StopAnimation()
+	device.ClearDimmingSchedule()
+	if got := device.GetBrightness(); got != 10 || device.UpdateSchedule() {
+		t.Errorf("FAIL: ClearDimmingSchedule() should restore 10, got %d", got)
+	}
+}
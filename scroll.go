@@ -0,0 +1,139 @@
+package ht16k33
+
+import "time"
+
+// scrollState is a running marquee on one display, or on both displays for
+// combinedDisplay.
+//
+// scrollStateは、1つのディスプレイ(combinedDisplayなら両方)で動いている
+// マーキー。
+type scrollState struct {
+	active   bool
+	display  int
+	cells    []cell
+	offset   int
+	interval time.Duration
+	last     uint32
+}
+
+// StartScroll starts scrolling s from right to left across a display,
+// looping until StopScroll. Each loop starts with s left-aligned, and s
+// scrolls out completely before it comes in again from the right. Text that
+// fits is shown without scrolling. It is non-blocking: call UpdateScroll
+// from the main loop, like UpdateFade. Each display can scroll its own text.
+//
+// StartScrollは、sをディスプレイ上で右から左へスクロールし始め、StopScroll
+// まで繰り返す。毎回sを左寄せにした状態から始め、sが完全に出て行ってから
+// 再び右から入ってくる。収まるテキストはスクロールせずに表示する。
+// ノンブロッキングなので、UpdateFadeと同様にメインループからUpdateScrollを
+// 呼び出す。ディスプレイごとに別のテキストをスクロールできる。
+func (d *Device) StartScroll(display int, s string, interval time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	return d.startScroll(display, s, interval)
+}
+
+// StartScroll16 is like StartScroll, but scrolls s across both displays as
+// one continuous 16-digit strip, so it flows from display A into display B
+// the same way SetDigit16 addresses them. It replaces any scrolling on
+// either display, and StopScroll on either display stops it.
+//
+// StartScroll16はStartScrollと同様だが、SetDigit16と同じように両方の
+// ディスプレイを1本の16桁の帯として扱い、sをディスプレイAからBへ流れるように
+// スクロールする。どちらのディスプレイのスクロールも置き換え、どちらの
+// ディスプレイのStopScrollでも止まる。
+func (d *Device) StartScroll16(s string, interval time.Duration) error {
+	return d.startScroll(combinedDisplay, s, interval)
+}
+
+// startScroll is the common part of StartScroll and StartScroll16.
+//
+// startScrollは、StartScrollとStartScroll16の共通部分。
+func (d *Device) startScroll(display int, s string, interval time.Duration) error {
+	cells, _, err := d.layoutString(s)
+	if err != nil {
+		return err
+	}
+	slot := display
+	if display == combinedDisplay {
+		d.StopScroll(1)
+		slot = 0
+	}
+	d.StopScroll(slot)
+	scroll := &d.scrolls[slot]
+	*scroll = scrollState{display: display, cells: cells, interval: interval, last: d.tick()}
+	scroll.active = len(cells) > d.width(display)
+	d.drawScroll(scroll)
+	d.Display()
+	return nil
+}
+
+// StopScroll stops the scrolling on a display and leaves its current frame
+// shown.
+//
+// StopScrollは、ディスプレイのスクロールを止め、現在のフレームを表示した
+// ままにする。
+func (d *Device) StopScroll(display int) {
+	for i := range d.scrolls {
+		scroll := &d.scrolls[i]
+		if scroll.display == display || scroll.display == combinedDisplay {
+			scroll.active = false
+		}
+	}
+}
+
+// UpdateScroll drives the scrolling started by StartScroll, moving each
+// scrolling display by one digit per interval. It returns true while any
+// display is scrolling.
+//
+// UpdateScrollは、StartScrollで始めたスクロールを動かし、スクロール中の
+// 各ディスプレイをintervalごとに1桁ずつ動かす。いずれかのディスプレイが
+// スクロール中であればtrueを返す。
+func (d *Device) UpdateScroll() bool {
+	if d.paused {
+		return d.IsScrolling()
+	}
+	changed := false
+	for i := range d.scrolls {
+		scroll := &d.scrolls[i]
+		if !scroll.active || d.sinceTick(scroll.last) < scroll.interval {
+			continue
+		}
+		scroll.last = d.tick()
+		// One loop is the text followed by a blank display.
+		scroll.offset = (scroll.offset + 1) % (len(scroll.cells) + d.width(scroll.display))
+		d.drawScroll(scroll)
+		changed = true
+	}
+	if changed {
+		d.Display()
+	}
+	return d.IsScrolling()
+}
+
+// IsScrolling returns true if any display is scrolling.
+//
+// IsScrollingは、いずれかのディスプレイがスクロール中であればtrueを返す。
+func (d *Device) IsScrolling() bool {
+	for _, scroll := range d.scrolls {
+		if scroll.active {
+			return true
+		}
+	}
+	return false
+}
+
+// drawScroll draws the current frame of a scroll into the buffer.
+//
+// drawScrollは、スクロールの現在のフレームをバッファに描く。
+func (d *Device) drawScroll(scroll *scrollState) {
+	d.clearSurface(scroll.display)
+	width := d.width(scroll.display)
+	loop := len(scroll.cells) + width
+	for pos := 0; pos < width; pos++ {
+		if i := (scroll.offset + pos) % loop; i < len(scroll.cells) {
+			d.placeOn(scroll.display, pos, scroll.cells[i])
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ht16k33
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// stepScroll makes the next UpdateScroll move every scroll by one digit.
+func stepScroll(device *Device) bool {
+	advanceClock(device, time.Hour)
+	return device.UpdateScroll()
+}
+
+// TestScroll verifies the frames of a looping marquee.
+func TestScroll(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+
+	if err := device.StartScroll(0, "HELLO 123", time.Second); err != nil {
+		t.Fatalf("StartScroll() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "HELLO 12")
+	if !bytes.Equal(mockBus.data[1:], device.buffer[:]) {
+		t.Errorf("FAIL: First frame was not sent!\nExpected: %x\nGot:      %x", device.buffer[:], mockBus.data[1:])
+	}
+
+	if device.UpdateScroll(); !device.IsScrolling() {
+		t.Fatalf("FAIL: IsScrolling() should return true")
+	}
+	assertDisplay(t, device, 0, "HELLO 12") // The interval has not passed yet
+
+	stepScroll(device)
+	assertDisplay(t, device, 0, "ELLO 123")
+	for i := 0; i < 8; i++ {
+		stepScroll(device)
+	}
+	assertDisplay(t, device, 0, "")
+	stepScroll(device)
+	assertDisplay(t, device, 0, "       H")
+	for i := 0; i < 7; i++ {
+		stepScroll(device)
+	}
+	assertDisplay(t, device, 0, "HELLO 12")
+
+	device.StopScroll(0)
+	if stepScroll(device) {
+		t.Errorf("FAIL: UpdateScroll() should return false after StopScroll")
+	}
+	assertDisplay(t, device, 0, "HELLO 12")
+}
+
+// TestScrollShortText verifies that text that fits does not scroll.
+func TestScrollShortText(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.StartScroll(1, "HI", time.Second)
+	if device.IsScrolling() {
+		t.Errorf("FAIL: IsScrolling() should return false for text that fits")
+	}
+	assertDisplay(t, device, 1, "HI")
+	if err := device.StartScroll(2, "HI", time.Second); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: StartScroll() on display 2 should return ErrInvalidDisplay, got %v", err)
+	}
+}
+
+// TestScroll16 verifies that the text flows from display A into display B.
+func TestScroll16(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.StartScroll(1, "SCROLLING", time.Second)
+
+	if err := device.StartScroll16("0123456789ABCDEF.G", time.Second); err != nil {
+		t.Fatalf("StartScroll16() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "01234567")
+	assertDisplay(t, device, 1, "89ABCDEF.")
+
+	stepScroll(device)
+	assertDisplay(t, device, 0, "12345678")
+	assertDisplay(t, device, 1, "9ABCDEF.G")
+
+	device.StopScroll(1)
+	if device.IsScrolling() {
+		t.Errorf("FAIL: StopScroll(1) should stop the combined scroll")
+	}
+}
@@ -0,0 +1,133 @@
+package ht16k33
+
+// Segment identifies one segment of a 7-segment digit.
+//
+// Segmentは、7セグメントの桁のうちの1つのセグメントを表す。
+type Segment uint8
+
+// The segments of a digit, in the same order as the ROW lines.
+//
+// 桁のセグメント。ROWの並びと同じ順番。
+const (
+	SegmentA Segment = iota
+	SegmentB
+	SegmentC
+	SegmentD
+	SegmentE
+	SegmentF
+	SegmentG
+	SegmentDP
+)
+
+// Segments is a segment pattern of one digit (bits 0-6 are segments a-g),
+// with helpers to build it readably, e.g. NewSegments().A().F().G().
+// Pass it to SetSegments with its Byte method.
+//
+// Segmentsは、1桁分のセグメントパターン(ビット0-6がセグメントa-g)。
+// NewSegments().A().F().G()のように読みやすく組み立てるためのヘルパーを持つ。
+// SetSegmentsにはByteメソッドで渡す。
+type Segments byte
+
+// allSegments is the pattern with every segment a-g lit.
+const allSegments Segments = 0x7F
+
+// NewSegments returns an empty pattern.
+//
+// NewSegmentsは、空のパターンを返す。
+func NewSegments() Segments { return 0 }
+
+// A returns the pattern with segment a (top) added.
+func (s Segments) A() Segments { return s | Segments(segA) }
+
+// B returns the pattern with segment b (upper right) added.
+func (s Segments) B() Segments { return s | Segments(segB) }
+
+// C returns the pattern with segment c (lower right) added.
+func (s Segments) C() Segments { return s | Segments(segC) }
+
+// D returns the pattern with segment d (bottom) added.
+func (s Segments) D() Segments { return s | Segments(segD) }
+
+// E returns the pattern with segment e (lower left) added.
+func (s Segments) E() Segments { return s | Segments(segE) }
+
+// F returns the pattern with segment f (upper left) added.
+func (s Segments) F() Segments { return s | Segments(segF) }
+
+// G returns the pattern with segment g (middle) added.
+func (s Segments) G() Segments { return s | Segments(segG) }
+
+// Union returns the segments lit in either s or other.
+//
+// Unionは、sまたはotherのどちらかで点灯するセグメントを返す。
+func (s Segments) Union(other Segments) Segments { return (s | other) & allSegments }
+
+// Invert returns the segments a-g that are not lit in s.
+//
+// Invertは、sで点灯していないセグメントa-gを返す。
+func (s Segments) Invert() Segments { return ^s & allSegments }
+
+// Has returns true if seg (SegmentA-SegmentG) is lit in s.
+//
+// Hasは、sでseg(SegmentA-SegmentG)が点灯していればtrueを返す。
+func (s Segments) Has(seg Segment) bool { return seg < SegmentDP && s&(1<<seg) != 0 }
+
+// Byte returns the pattern as taken by SetSegments.
+//
+// Byteは、SetSegmentsが受け取る形でパターンを返す。
+func (s Segments) Byte() byte { return byte(s & allSegments) }
+
+// SetSegment turns a single segment of a digit on or off, leaving the other
+// segments of that digit as they are.
+//
+// SetSegmentは、桁の1つのセグメントだけを点灯または消灯する。
+// その桁の他のセグメントはそのまま。
+//
+// display: 0 for the first display (A), 1 for the second (B)
+// position: 0-7, the digit position
+// seg: the segment to change (SegmentA-SegmentG, or SegmentDP)
+// on: true to light up the segment
+func (d *Device) SetSegment(display int, position int, seg Segment, on bool) {
+	row, ok := d.segmentRow(display, position, seg)
+	if !ok {
+		return
+	}
+	if on {
+		d.buffer[row] |= 1 << position
+	} else {
+		d.buffer[row] &^= 1 << position
+	}
+	d.autoDisplay()
+}
+
+// ToggleSegment inverts a single segment of a digit.
+//
+// ToggleSegmentは、桁の1つのセグメントを反転させる。
+func (d *Device) ToggleSegment(display int, position int, seg Segment) {
+	row, ok := d.segmentRow(display, position, seg)
+	if !ok {
+		return
+	}
+	d.buffer[row] ^= 1 << position
+	d.autoDisplay()
+}
+
+// SetDot turns the decimal point of a digit on or off without changing the
+// character shown on it.
+//
+// SetDotは、桁に表示している文字を変えずに、小数点を点灯または消灯する。
+func (d *Device) SetDot(display int, position int, on bool) {
+	d.SetSegment(display, position, SegmentDP, on)
+}
+
+// segmentRow returns the buffer row that holds seg of the given display,
+// and false if any of the arguments is out of range.
+//
+// segmentRowは、指定したディスプレイのsegを保持するバッファの行を返す。
+// 引数が範囲外ならfalseを返す。
+func (d *Device) segmentRow(display int, position int, seg Segment) (int, bool) {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits || seg > SegmentDP {
+		return 0, false
+	}
+	return display*segmentRows + int(seg), true
+}
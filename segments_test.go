@@ -0,0 +1,61 @@
+package ht16k33
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestSetSegment verifies that single segments can be set and toggled.
+func TestSetSegment(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetDigitOnDisplay(0, 3, '1', false) // segments b and c
+
+	device.SetSegment(0, 3, SegmentG, true)
+	device.SetSegment(0, 3, SegmentB, false)
+	device.ToggleSegment(0, 3, SegmentDP)
+	device.ToggleSegment(0, 3, SegmentC)
+	// Out of range arguments are ignored.
+	device.SetSegment(0, 8, SegmentA, true)
+	device.SetSegment(0, 0, SegmentDP+1, true)
+
+	expectedBuffer := [16]byte{6: 1 << 3, 7: 1 << 3}
+	if !bytes.Equal(device.buffer[:], expectedBuffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedBuffer[:], device.buffer[:])
+	}
+}
+
+// TestSetDot verifies that only the dot row changes.
+func TestSetDot(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(1, "12")
+
+	device.SetDot(1, 0, true)
+	expected := device.buffer
+	device.SetDot(1, 1, true)
+	device.SetDot(1, 1, false)
+
+	if device.buffer != expected {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expected[:], device.buffer[:])
+	}
+	if device.buffer[15] != 1<<0 {
+		t.Errorf("FAIL: Dot row is %08b, expected %08b", device.buffer[15], 1<<0)
+	}
+}
+
+// TestSegmentsBuilder verifies the fluent pattern helpers.
+func TestSegmentsBuilder(t *testing.T) {
+	top := NewSegments().A().F().B()
+	if top.Byte() != segA|segF|segB {
+		t.Errorf("FAIL: NewSegments().A().F().B() = %07b", top.Byte())
+	}
+	bottom := NewSegments().E().D().C()
+	if got := top.Union(bottom).G(); got.Byte() != segA|segB|segC|segD|segE|segF|segG {
+		t.Errorf("FAIL: Union().G() = %07b", got.Byte())
+	}
+	if got := top.Invert(); got.Byte() != segC|segD|segE|segG {
+		t.Errorf("FAIL: Invert() = %07b", got.Byte())
+	}
+	if !top.Has(SegmentF) || top.Has(SegmentG) || top.Has(SegmentDP) {
+		t.Errorf("FAIL: Has() is wrong for %07b", top.Byte())
+	}
+}
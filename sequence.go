@@ -0,0 +1,133 @@
+package ht16k33
+
+import "time"
+
+// SequenceStep is one step of a Sequence, made by Action, Effect or Hold.
+//
+// SequenceStepは、Sequenceの1つのステップで、Action、Effect、Holdで作る。
+type SequenceStep struct {
+	action func(d *Device)
+	// await waits for the effect the action started to finish.
+	// awaitは、actionが始めた効果が終わるのを待つ。
+	await bool
+	hold  time.Duration
+}
+
+// Action is a step that calls action once, such as writing the next text,
+// and goes on at once.
+//
+// Actionは、次のテキストを書くなど、actionを一度呼び出してすぐに次へ進む
+// ステップ。
+func Action(action func(d *Device)) SequenceStep {
+	return SequenceStep{action: action}
+}
+
+// Effect is a step that calls start, which should start an effect run by
+// the Animator (such as StartFade or WipeTo), and goes on once that effect
+// has finished, as OnDone would report.
+//
+// Effectは、Animatorが動かす効果(StartFadeやWipeToなど)を始めるstartを
+// 呼び出し、OnDoneが知らせるようにその効果が終わったら次へ進むステップ。
+func Effect(start func(d *Device)) SequenceStep {
+	return SequenceStep{action: start, await: true}
+}
+
+// Hold is a step that waits for duration, keeping the display as it is.
+// Other effects, such as a scroll started by an Action, keep running.
+//
+// Holdは、ディスプレイをそのままにしてdurationだけ待つステップ。Actionで
+// 始めたスクロールなど、他の効果は動き続ける。
+func Hold(duration time.Duration) SequenceStep {
+	return SequenceStep{hold: duration}
+}
+
+// Sequence is an Animation that plays steps one after another, so that a
+// show such as "fade in X, hold 2s, scroll Y" is written as a list instead
+// of a state machine in the main loop:
+//
+//	d.Animate(ht16k33.NewSequence(
+//		ht16k33.Effect(func(d *ht16k33.Device) { d.WriteString(0, "X"); d.StartFade(delay) }),
+//		ht16k33.Hold(2*time.Second),
+//		ht16k33.Action(func(d *ht16k33.Device) { d.StartScroll(0, "Y", interval) }),
+//	))
+//
+// Sequenceは、ステップを順に再生するAnimationで、「Xをフェードで表示し、
+// 2秒保ち、Yをスクロールする」のような演出を、メインループの状態機械の
+// 代わりに上の例のようなリストで書ける。
+type Sequence struct {
+	steps   []SequenceStep
+	loop    bool
+	index   int
+	started bool
+	since   uint32
+	waiting Animation
+	stopped bool
+}
+
+// NewSequence creates a Sequence of steps. Start it with Animate.
+//
+// NewSequenceは、ステップからSequenceを作る。Animateで開始する。
+func NewSequence(steps ...SequenceStep) *Sequence {
+	return &Sequence{steps: steps}
+}
+
+// SetLoop makes the Sequence start again from its first step after the
+// last one, until it is stopped.
+//
+// SetLoopは、Sequenceが最後のステップの後に最初のステップから再び始まる
+// ようにし、止めるまで繰り返す。
+func (s *Sequence) SetLoop(loop bool) {
+	s.loop = loop
+}
+
+// Start begins at the first step.
+func (s *Sequence) Start(d *Device, now time.Time) {
+	s.index = 0
+	s.started = false
+	s.stopped = false
+}
+
+// Step runs the steps that are due. Each step runs at most once per call,
+// so a loop of steps that all go on at once does not hang Update.
+func (s *Sequence) Step(d *Device, now time.Time) bool {
+	changed := false
+	for ran := 0; !s.Done() && ran < len(s.steps); ran++ {
+		step := s.steps[s.index]
+		if !s.started {
+			s.started = true
+			s.since = tickOf(now)
+			s.waiting = nil
+			if step.action != nil {
+				last := d.lastAnimation
+				step.action(d)
+				changed = true
+				if step.await && d.lastAnimation != last && d.lastAnimation != nil {
+					s.waiting = d.lastAnimation
+				}
+			}
+		}
+		if s.waiting != nil && !s.waiting.Done() {
+			break
+		}
+		if tickDuration(tickOf(now)-s.since) < step.hold {
+			break
+		}
+		s.started = false
+		s.index++
+		if s.index == len(s.steps) && s.loop {
+			s.index = 0
+		}
+	}
+	return changed
+}
+
+// Done reports whether the last step has finished or the Sequence was
+// stopped.
+func (s *Sequence) Done() bool {
+	return s.stopped || s.index >= len(s.steps)
+}
+
+// Stop ends the Sequence where it is, leaving the remaining steps.
+func (s *Sequence) Stop(d *Device) {
+	s.stopped = true
+}
@@ -0,0 +1,68 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSequence verifies that the steps run in order, waiting for effects and holds.
+func TestSequence(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	var log []string
+	seq := NewSequence(
+		Action(func(d *Device) { log = append(log, "write"); d.WriteString(0, "1") }),
+		Effect(func(d *Device) { log = append(log, "wipe"); d.WipeTo(0, "22", TransitionLeftToRight, 0) }),
+		Action(func(d *Device) { log = append(log, "after wipe") }),
+		Hold(time.Hour),
+		Action(func(d *Device) { log = append(log, "after hold") }),
+	)
+	device.Animate(seq)
+
+	device.Update()
+	if len(log) != 2 {
+		t.Fatalf("FAIL: Actions should run until the first effect, got %v", log)
+	}
+	assertDisplay(t, device, 0, "1")
+	for i := 0; i < 8; i++ {
+		device.Update()
+	}
+	assertDisplay(t, device, 0, "22")
+	if len(log) != 2 {
+		t.Fatalf("FAIL: Sequence should wait for the wipe, got %v", log)
+	}
+	device.Update()
+	if len(log) != 3 {
+		t.Fatalf("FAIL: Sequence should go on after the wipe, got %v", log)
+	}
+
+	// The hold is waited out in the time of the animations.
+	device.animator.Step(device, device.animationTime().Add(time.Hour-time.Second))
+	if len(log) != 3 {
+		t.Fatalf("FAIL: Sequence should hold, got %v", log)
+	}
+	device.animator.Step(device, device.animationTime().Add(time.Hour))
+	if len(log) != 4 || !seq.Done() || device.IsAnimating() {
+		t.Errorf("FAIL: Sequence should finish after the hold, got %v", log)
+	}
+}
+
+// TestSequenceLoop verifies that a looping sequence starts again and runs each step at most once per Update.
+func TestSequenceLoop(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	count := 0
+	seq := NewSequence(Action(func(d *Device) { count++ }), Action(func(d *Device) { count++ }))
+	seq.SetLoop(true)
+	device.Animate(seq)
+
+	device.Update()
+	device.Update()
+	if count != 4 || seq.Done() {
+		t.Errorf("FAIL: Looping sequence should run each step once per Update, got %d runs", count)
+	}
+	device.StopAnimation()
+	if !seq.Done() || device.IsAnimating() {
+		t.Errorf("FAIL: StopAnimation() should stop a looping sequence")
+	}
+}
@@ -0,0 +1,196 @@
+package ht16k33
+
+// BlinkRate is the rate at which the chip itself blinks the whole display,
+// set with SetHardwareBlink.
+//
+// BlinkRateは、SetHardwareBlinkで設定する、チップ自身がディスプレイ全体を
+// 点滅させる速さ。
+type BlinkRate uint8
+
+const (
+	// BlinkOff turns the hardware blink off (default).
+	BlinkOff BlinkRate = iota
+	// Blink2Hz blinks twice a second.
+	Blink2Hz
+	// Blink1Hz blinks once a second.
+	Blink1Hz
+	// Blink0_5Hz blinks once every two seconds.
+	Blink0_5Hz
+)
+
+// SetHardwareBlink makes the chip blink the whole display at rate, without
+// any CPU time or calls to Update, for example for an alarm clock. Unlike
+// SetBlink, which blinks single digits from Update, it always blinks both
+// displays entirely.
+//
+// SetHardwareBlinkは、チップにディスプレイ全体をrateの速さで点滅させる。
+// CPU時間もUpdateの呼び出しも必要なく、たとえば目覚まし時計に使える。
+// Updateから個々の桁を点滅させるSetBlinkと違い、常に両方のディスプレイ全体
+// を点滅させる。
+func (d *Device) SetHardwareBlink(rate BlinkRate) {
+	d.hardwareBlink = rate & 0x03
+	d.ApplySetup()
+}
+
+// HardwareBlink returns the rate set with SetHardwareBlink.
+//
+// HardwareBlinkは、SetHardwareBlinkで設定した速さを返す。
+func (d *Device) HardwareBlink() BlinkRate {
+	return d.hardwareBlink
+}
+
+// DisplayOn turns the display on again after DisplayOff, showing the chip
+// RAM as it was.
+//
+// DisplayOnは、DisplayOffの後にディスプレイを再びオンにし、チップのRAMを
+// そのまま表示する。
+func (d *Device) DisplayOn() {
+	d.displayOn = true
+	d.ApplySetup()
+}
+
+// DisplayOff turns the display off with the display setup command only, so
+// the buffer and the chip RAM stay intact and DisplayOn restores the screen
+// at once.
+//
+// DisplayOffは、表示設定のコマンドだけでディスプレイをオフにするので、
+// バッファとチップのRAMはそのまま残り、DisplayOnですぐに画面を元に戻せる。
+func (d *Device) DisplayOff() {
+	d.displayOn = false
+	d.ApplySetup()
+}
+
+// Standby turns the display and the oscillator off, so the chip draws only
+// its standby current, for example between updates of a battery-powered
+// build. The chip keeps its RAM, and Wake turns it on again.
+//
+// Standbyは、ディスプレイとオシレーターをオフにし、チップがスタンバイ電流
+// しか流さないようにする。たとえば電池駆動の場合に更新の合間に使う。チップは
+// RAMを保持し、Wakeで再びオンにする。
+func (d *Device) Standby() error {
+	d.standby = true
+	return d.ApplySetup()
+}
+
+// Wake ends Standby: it turns the oscillator on, restores the display setup
+// and the brightness, and sends the buffer again. It returns the first I2C
+// error.
+//
+// Wakeは、Standbyを終える。オシレーターをオンにし、表示設定と明るさを元に
+// 戻し、バッファを再び送る。最初に起きたI2Cのエラーを返す。
+func (d *Device) Wake() error {
+	d.standby = false
+	d.chipUnknown = true
+	if err := d.ApplySetup(); err != nil {
+		return err
+	}
+	frame := d.frame()
+	return d.tx(append([]byte{0x00}, frame[:]...), nil)
+}
+
+// IsStandby returns true between Standby and Wake.
+//
+// IsStandbyは、StandbyからWakeまでの間にtrueを返す。
+func (d *Device) IsStandby() bool {
+	return d.standby
+}
+
+// chipSetup is the setup state of the chip. Its zero value is the state
+// after power-on.
+//
+// chipSetupは、チップの設定の状態。ゼロ値は電源投入後の状態。
+type chipSetup struct {
+	oscillator bool
+	display    byte
+	brightness uint8
+	rowInt     byte
+}
+
+// wantedSetup returns the setup the chip should have for the Device.
+//
+// wantedSetupは、Deviceに合わせてチップが持つべき設定を返す。
+func (d *Device) wantedSetup() chipSetup {
+	setup := chipSetup{
+		oscillator: d.oscillatorOn && !d.standby,
+		display:    byte(d.hardwareBlink) << 1,
+		brightness: d.currentBrightness,
+	}
+	if d.displayOn && !d.standby {
+		setup.display |= 0x01
+	}
+	switch d.interruptMode {
+	case InterruptActiveLow:
+		setup.rowInt = 0x01
+	case InterruptActiveHigh:
+		setup.rowInt = 0x03
+	}
+	return setup
+}
+
+// ApplySetup sends the setup of the Device (oscillator, display on, blink,
+// brightness and ROW15/INT) to the chip, but only the commands whose state
+// differs from what was last sent, so features can change the setup freely
+// without extra I2C traffic. The setters such as SetBrightness and
+// DisplayOff call it; it is needed directly only to retry after an error.
+// The oscillator is turned on first and off last, and the brightness is
+// sent after the display setup. It returns the first I2C error, after
+// which the whole setup is sent again on the next call.
+//
+// ApplySetupは、Deviceの設定(オシレーター、表示オン、点滅、明るさ、
+// ROW15/INT)をチップに送る。ただし最後に送った状態と違うコマンドだけ
+// なので、機能は余分なI2Cの通信なしに自由に設定を変えられる。
+// SetBrightnessやDisplayOffなどの設定関数が呼び出すので、直接必要なのは
+// エラーの後に再試行する場合だけ。オシレーターは最初にオンにして最後に
+// オフにし、明るさは表示設定の後に送る。最初に起きたI2Cのエラーを返し、
+// その後は次の呼び出しで設定全体を再び送る。
+func (d *Device) ApplySetup() error {
+	want := d.wantedSetup()
+	force := d.chipUnknown
+	// Until every command has been sent, the chip is not known.
+	d.chipUnknown = true
+	if want.oscillator && (force || !d.chip.oscillator) {
+		if err := d.tx([]byte{ht16k33TurnOnOscillator}, nil); err != nil {
+			return err
+		}
+	}
+	// ROW15/INT is left at its power-on default until it is changed.
+	if want.rowInt != d.chip.rowInt || force && want.rowInt != 0 {
+		if err := d.tx([]byte{ht16k33RowIntSet | want.rowInt}, nil); err != nil {
+			return err
+		}
+	}
+	if force || want.display != d.chip.display {
+		if err := d.tx([]byte{ht16k33DisplaySetup | want.display}, nil); err != nil {
+			return err
+		}
+	}
+	if force || want.brightness != d.chip.brightness {
+		if err := d.tx([]byte{ht16k33SetBrightness | want.brightness}, nil); err != nil {
+			return err
+		}
+	}
+	if !want.oscillator && (force || d.chip.oscillator) {
+		if err := d.tx([]byte{ht16k33TurnOffOscillator}, nil); err != nil {
+			return err
+		}
+	}
+	d.chip = want
+	d.chipUnknown = false
+	return nil
+}
+
+// Reset runs the whole initialization again in one call, for example after
+// a brown-out or when the display module has been plugged in again: it
+// turns the oscillator and the display on, sends the whole setup including
+// the brightness, and overwrites the display RAM with the buffer, which
+// keeps its content. It returns the first I2C error.
+//
+// Resetは、初期化全体を1回の呼び出しでやり直す。たとえば電圧低下の後や
+// ディスプレイモジュールを挿し直したときに使う。オシレーターとディスプレイ
+// をオンにし、明るさを含む設定全体を送り、表示用RAMを内容を保ったバッファで
+// 上書きする。最初に起きたI2Cのエラーを返す。
+func (d *Device) Reset() error {
+	d.oscillatorOn = true
+	d.displayOn = true
+	return d.Wake()
+}
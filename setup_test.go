@@ -0,0 +1,141 @@
+package ht16k33
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSetHardwareBlink verifies the display setup command sent for each blink rate.
+func TestSetHardwareBlink(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	if err := device.Configure(Config{}); err != nil {
+		t.Fatalf("Configure() returned an unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		rate     BlinkRate
+		expected byte
+	}{
+		{Blink2Hz, 0x83},
+		{Blink1Hz, 0x85},
+		{Blink0_5Hz, 0x87},
+		{BlinkOff, 0x81},
+	}
+	for _, tc := range testCases {
+		device.SetHardwareBlink(tc.rate)
+		if len(mockBus.data) != 1 || mockBus.data[0] != tc.expected {
+			t.Errorf("FAIL: Display setup is wrong!\nExpected: %x\nGot:      %x", tc.expected, mockBus.data)
+		}
+	}
+	if got := device.HardwareBlink(); got != BlinkOff {
+		t.Errorf("FAIL: HardwareBlink() = %d, expected BlinkOff", got)
+	}
+}
+
+// TestDisplayOnOff verifies that only the display on bit changes and the RAM is kept.
+func TestDisplayOnOff(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.Configure(Config{})
+	device.SetHardwareBlink(Blink1Hz)
+	device.WriteString(0, "8")
+	device.Display()
+	ram := mockBus.ram
+
+	device.DisplayOff()
+	if device.IsDisplayOn() || mockBus.data[0] != 0x84 {
+		t.Errorf("FAIL: DisplayOff() sent the wrong setup!\nExpected: %x\nGot:      %x", 0x84, mockBus.data)
+	}
+	device.DisplayOn()
+	if !device.IsDisplayOn() || mockBus.data[0] != 0x85 {
+		t.Errorf("FAIL: DisplayOn() sent the wrong setup!\nExpected: %x\nGot:      %x", 0x85, mockBus.data)
+	}
+	if mockBus.ram != ram || device.buffer != ram {
+		t.Errorf("FAIL: DisplayOff() and DisplayOn() should keep the RAM, got %x", mockBus.ram)
+	}
+}
+
+// TestStandbyWake verifies that Standby turns the oscillator off and Wake sends the buffer again.
+func TestStandbyWake(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.Configure(Config{})
+	device.WriteString(1, "42")
+
+	if err := device.Standby(); err != nil {
+		t.Fatalf("Standby() returned an unexpected error: %v", err)
+	}
+	if !device.IsStandby() || mockBus.data[0] != ht16k33TurnOffOscillator {
+		t.Errorf("FAIL: Standby() should turn the oscillator off, got %x", mockBus.data)
+	}
+	transactions := device.Stats().Transactions
+	device.DisplayOn()
+	if device.Stats().Transactions != transactions {
+		t.Errorf("FAIL: DisplayOn() should keep the display off in standby, got %x", mockBus.data)
+	}
+
+	if err := device.Wake(); err != nil {
+		t.Fatalf("Wake() returned an unexpected error: %v", err)
+	}
+	if device.IsStandby() || !device.IsDisplayOn() || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: Wake() should send the buffer again!\nExpected: %x\nGot:      %x", device.buffer, mockBus.ram)
+	}
+}
+
+// TestApplySetup verifies that only the changed setup is sent, and everything again after an error.
+func TestApplySetup(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.Configure(Config{Brightness: 5})
+
+	transactions := device.Stats().Transactions
+	device.SetBrightness(5)
+	device.DisplayOn()
+	device.SetHardwareBlink(BlinkOff)
+	if err := device.ApplySetup(); err != nil || device.Stats().Transactions != transactions {
+		t.Errorf("FAIL: Unchanged setup should not be sent, got %d transactions", device.Stats().Transactions-transactions)
+	}
+
+	device.SetBrightness(6)
+	if got := device.Stats().Transactions - transactions; got != 1 || mockBus.data[0] != 0xE6 {
+		t.Errorf("FAIL: Only the brightness should be sent, got %d transactions and %x", got, mockBus.data)
+	}
+
+	mockBus.err = errors.New("nack")
+	device.SetHardwareBlink(Blink2Hz)
+	mockBus.err = nil
+	transactions = device.Stats().Transactions
+	if err := device.ApplySetup(); err != nil {
+		t.Fatalf("ApplySetup() returned an unexpected error: %v", err)
+	}
+	// Oscillator, display setup and brightness.
+	if got := device.Stats().Transactions - transactions; got != 3 || mockBus.data[0] != 0xE6 {
+		t.Errorf("FAIL: Setup should be sent in full after an error, got %d transactions", got)
+	}
+}
+
+// TestReset verifies that the setup and the buffer are sent again to a chip that lost them.
+func TestReset(t *testing.T) {
+	mockBus := &mockI2C{}
+	device := newTestDevice(t, mockBus)
+	device.Configure(Config{Brightness: 3})
+	device.SetInterruptMode(InterruptActiveLow)
+	device.WriteString(0, "7")
+	device.Display()
+	device.Standby()
+
+	// The module was plugged in again.
+	mockBus.ram = [16]byte{}
+	transactions := device.Stats().Transactions
+	if err := device.Reset(); err != nil {
+		t.Fatalf("Reset() returned an unexpected error: %v", err)
+	}
+	// Oscillator, ROW/INT, display setup, brightness and the RAM.
+	if got := device.Stats().Transactions - transactions; got != 5 {
+		t.Errorf("FAIL: Reset() sent %d transactions, expected 5", got)
+	}
+	if device.IsStandby() || !device.IsDisplayOn() || device.GetBrightness() != 3 || mockBus.ram != device.buffer {
+		t.Errorf("FAIL: Reset() should restore the chip!\nExpected: %x\nGot:      %x", device.buffer, mockBus.ram)
+	}
+}
@@ -0,0 +1,138 @@
+package ht16k33
+
+import "time"
+
+// snakeSegment is one segment on the path of a snake.
+//
+// snakeSegmentは、スネークの経路上の1つのセグメント。
+type snakeSegment struct {
+	position int
+	segment  byte
+}
+
+// snakePath returns a closed path of touching segments around the outside
+// of width digits: along the top from the left, down the right end, back
+// along the bottom and up the left end.
+//
+// snakePathは、width桁の外周を回る、接したセグメントの閉じた経路を返す。
+// 左から上辺に沿って進み、右端を下り、下辺に沿って戻り、左端を上る。
+func snakePath(width int) []snakeSegment {
+	path := make([]snakeSegment, 0, 2*width+4)
+	for pos := 0; pos < width; pos++ {
+		path = append(path, snakeSegment{pos, segA})
+	}
+	path = append(path, snakeSegment{width - 1, segB}, snakeSegment{width - 1, segC})
+	for pos := width - 1; pos >= 0; pos-- {
+		path = append(path, snakeSegment{pos, segD})
+	}
+	return append(path, snakeSegment{0, segE}, snakeSegment{0, segF})
+}
+
+// snakeAnimation is the Animation started by StartSnake.
+//
+// snakeAnimationは、StartSnakeで始めるAnimation。
+type snakeAnimation struct {
+	display  int
+	path     []snakeSegment
+	length   int
+	head     int
+	interval time.Duration
+	last     uint32
+	// saved is the buffer before the snake, shown again when it stops.
+	// savedは、スネークの前のバッファで、止めたときに再び表示する。
+	saved   [16]byte
+	stopped bool
+}
+
+// Start draws the snake at the start of the path.
+func (s *snakeAnimation) Start(d *Device, now time.Time) {
+	s.saved = d.buffer
+	s.last = tickOf(now)
+	s.draw(d)
+	d.Display()
+}
+
+// Step moves the snake on by one segment per interval.
+func (s *snakeAnimation) Step(d *Device, now time.Time) bool {
+	if s.stopped || tickDuration(tickOf(now)-s.last) < s.interval {
+		return false
+	}
+	s.last = tickOf(now)
+	s.head = (s.head + 1) % len(s.path)
+	s.draw(d)
+	return true
+}
+
+// Done reports whether the snake was stopped.
+func (s *snakeAnimation) Done() bool {
+	return s.stopped
+}
+
+// Stop restores the content under the snake like StopSnake, without
+// sending it.
+func (s *snakeAnimation) Stop(d *Device) {
+	d.stopSnake(s.display)
+}
+
+// draw clears the display and draws the segments of the snake, from its
+// head back along the path.
+//
+// drawは、ディスプレイをクリアし、スネークのセグメントを頭から経路を
+// さかのぼって描く。
+func (s *snakeAnimation) draw(d *Device) {
+	d.clearDisplay(s.display)
+	for i := 0; i < s.length; i++ {
+		seg := s.path[(s.head-i+len(s.path))%len(s.path)]
+		d.orPattern(s.display, seg.position, seg.segment, false)
+	}
+}
+
+// StartSnake shows a snake on a display as a screensaver: length lit
+// segments chase each other around the outside of all digits, moving one
+// segment per interval. length is limited to 1 up to the length of the
+// path. The content of the display is shown again by StopSnake. It is
+// non-blocking: call Update from the main loop.
+//
+// StartSnakeは、スクリーンセーバーとしてディスプレイにスネークを表示する。
+// length個の点灯したセグメントが、すべての桁の外周をintervalごとに
+// 1セグメントずつ連なって回る。lengthは1から経路の長さまでに制限する。
+// ディスプレイの内容はStopSnakeで再び表示する。ノンブロッキングなので、
+// メインループからUpdateを呼び出す。
+func (d *Device) StartSnake(display int, length int, interval time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	d.StopSnake(display)
+	path := snakePath(d.digits)
+	length = min(max(length, 1), len(path))
+	d.snakes[display] = &snakeAnimation{display: display, path: path, length: length, head: length - 1, interval: interval}
+	d.Animate(d.snakes[display])
+	return nil
+}
+
+// StopSnake stops the snake on a display and shows the content it had
+// before the snake.
+//
+// StopSnakeは、ディスプレイのスネークを止め、スネークの前の内容を表示する。
+func (d *Device) StopSnake(display int) {
+	if d.stopSnake(display) {
+		d.Display()
+	}
+}
+
+// stopSnake is StopSnake without sending the buffer. It returns true if
+// a snake was stopped.
+//
+// stopSnakeは、バッファを送らないStopSnake。スネークを止めた場合はtrueを
+// 返す。
+func (d *Device) stopSnake(display int) bool {
+	if display < 0 || display >= d.displays || d.snakes[display] == nil {
+		return false
+	}
+	snake := d.snakes[display]
+	snake.stopped = true
+	d.snakes[display] = nil
+	rows := d.buffer[display*segmentRows : (display+1)*segmentRows]
+	copy(rows, snake.saved[display*segmentRows:])
+	return true
+}
@@ -0,0 +1,48 @@
+package ht16k33
+
+import "testing"
+
+// TestSnakePath verifies that the path goes around the outside of the digits.
+func TestSnakePath(t *testing.T) {
+	path := snakePath(2)
+	expected := []snakeSegment{
+		{0, segA}, {1, segA}, {1, segB}, {1, segC}, {1, segD}, {0, segD}, {0, segE}, {0, segF},
+	}
+	if len(path) != len(expected) {
+		t.Fatalf("FAIL: Path should have %d segments, got %d", len(expected), len(path))
+	}
+	for i := range expected {
+		if path[i] != expected[i] {
+			t.Errorf("FAIL: Segment %d is wrong!\nExpected: %v\nGot:      %v", i, expected[i], path[i])
+		}
+	}
+}
+
+// TestStartSnake verifies that the snake moves along the path and the content comes back when it stops.
+func TestStartSnake(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "1234")
+
+	if err := device.StartSnake(0, 3, 0); err != nil {
+		t.Fatalf("StartSnake() returned an unexpected error: %v", err)
+	}
+	if got := device.digitPattern(0, 2); got != segA {
+		t.Errorf("FAIL: Head should be on the top of digit 2, got %x", got)
+	}
+	for i := 0; i < 8; i++ {
+		device.Update()
+	}
+	// The head has reached the bottom of digit 7, the tail is on its b.
+	if got := device.digitPattern(0, 7); got != segB|segC|segD {
+		t.Errorf("FAIL: Snake should turn around the right end!\nExpected: %x\nGot:      %x", segB|segC|segD, got)
+	}
+	if got := device.digitPattern(0, 0); got != 0 {
+		t.Errorf("FAIL: Other digits should be blank, got %x", got)
+	}
+
+	device.StopSnake(0)
+	assertDisplay(t, device, 0, "1234")
+	if device.Update() {
+		t.Errorf("FAIL: Snake should stop")
+	}
+}
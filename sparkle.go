@@ -0,0 +1,118 @@
+package ht16k33
+
+import (
+	"math/rand"
+	"time"
+)
+
+// sparkleAnimation is the Animation started by StartSparkle.
+//
+// sparkleAnimationは、StartSparkleで始めるAnimation。
+type sparkleAnimation struct {
+	display  int
+	density  float64
+	interval time.Duration
+	overlay  bool
+	last     uint32
+	saved    [16]byte
+	stopped  bool
+}
+
+// Start draws the first random segments.
+func (s *sparkleAnimation) Start(d *Device, now time.Time) {
+	s.saved = d.buffer
+	s.last = tickOf(now)
+	s.draw(d)
+	d.Display()
+}
+
+// Step draws new random segments once per interval.
+func (s *sparkleAnimation) Step(d *Device, now time.Time) bool {
+	if s.stopped || tickDuration(tickOf(now)-s.last) < s.interval {
+		return false
+	}
+	s.last = tickOf(now)
+	s.draw(d)
+	return true
+}
+
+// Done reports whether the sparkle was stopped.
+func (s *sparkleAnimation) Done() bool {
+	return s.stopped
+}
+
+// Stop restores the content under the sparkle like StopSparkle, without
+// sending it.
+func (s *sparkleAnimation) Stop(d *Device) {
+	d.stopSparkle(s.display)
+}
+
+// draw lights each segment of the display, including the decimal points,
+// with the probability of the density, over the saved content if overlay is
+// set.
+//
+// drawは、小数点も含めたディスプレイの各セグメントを密度の確率で点灯する。
+// overlayが設定されていれば、保存した内容の上に重ねる。
+func (s *sparkleAnimation) draw(d *Device) {
+	for row := s.display * segmentRows; row < (s.display+1)*segmentRows; row++ {
+		var bits byte
+		if s.overlay {
+			bits = s.saved[row]
+		}
+		for pos := 0; pos < d.digits; pos++ {
+			if rand.Float64() < s.density {
+				bits |= 1 << pos
+			}
+		}
+		d.buffer[row] = bits
+	}
+}
+
+// StartSparkle makes a display twinkle for decoration: once per interval,
+// each segment is lit at random with the probability density (0 to 1).
+// With overlay the current content stays lit under the sparkles, otherwise
+// only the sparkles are shown. The content is shown again by StopSparkle.
+// It is non-blocking: call Update from the main loop.
+//
+// StartSparkleは、装飾のためにディスプレイをきらめかせる。intervalごとに、
+// 各セグメントを確率density(0から1)でランダムに点灯する。overlayなら現在の
+// 内容をきらめきの下に点灯したままにし、そうでなければきらめきだけを表示
+// する。内容はStopSparkleで再び表示する。ノンブロッキングなので、
+// メインループからUpdateを呼び出す。
+func (d *Device) StartSparkle(display int, density float64, interval time.Duration, overlay bool) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	d.StopSparkle(display)
+	density = min(max(density, 0), 1)
+	d.sparkles[display] = &sparkleAnimation{display: display, density: density, interval: interval, overlay: overlay}
+	d.Animate(d.sparkles[display])
+	return nil
+}
+
+// StopSparkle stops the sparkle on a display and shows the content it had
+// before the sparkle.
+//
+// StopSparkleは、ディスプレイのきらめきを止め、きらめきの前の内容を表示する。
+func (d *Device) StopSparkle(display int) {
+	if d.stopSparkle(display) {
+		d.Display()
+	}
+}
+
+// stopSparkle is StopSparkle without sending the buffer. It returns true if
+// a sparkle was stopped.
+//
+// stopSparkleは、バッファを送らないStopSparkle。きらめきを止めた場合はtrueを
+// 返す。
+func (d *Device) stopSparkle(display int) bool {
+	if display < 0 || display >= d.displays || d.sparkles[display] == nil {
+		return false
+	}
+	sparkle := d.sparkles[display]
+	sparkle.stopped = true
+	d.sparkles[display] = nil
+	rows := d.buffer[display*segmentRows : (display+1)*segmentRows]
+	copy(rows, sparkle.saved[display*segmentRows:])
+	return true
+}
@@ -0,0 +1,41 @@
+package ht16k33
+
+import "testing"
+
+// TestStartSparkle verifies the density limits, the overlay and that the content comes back when stopped.
+func TestStartSparkle(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "1")
+	device.WriteString(1, "2")
+	content := device.GetBuffer()
+
+	device.StartSparkle(0, 1, 0, false)
+	for row := 0; row < segmentRows; row++ {
+		if device.buffer[row] != 0xFF {
+			t.Fatalf("FAIL: Density 1 should light every segment, got %x", device.buffer[:segmentRows])
+		}
+	}
+
+	device.StartSparkle(0, 0, 0, true)
+	device.Update()
+	if device.GetBuffer() != content {
+		t.Errorf("FAIL: Density 0 with overlay should only show the content, got %x", device.GetBuffer())
+	}
+
+	device.StartSparkle(0, -1, 0, false)
+	device.Update()
+	for row := 0; row < segmentRows; row++ {
+		if device.buffer[row] != 0 {
+			t.Fatalf("FAIL: Density below 0 should light nothing, got %x", device.buffer[:segmentRows])
+		}
+	}
+	assertDisplay(t, device, 1, "2")
+
+	device.StopSparkle(0)
+	if device.GetBuffer() != content {
+		t.Errorf("FAIL: StopSparkle() should show the content again, got %x", device.GetBuffer())
+	}
+	if device.Update() {
+		t.Errorf("FAIL: Sparkle should stop")
+	}
+}
@@ -0,0 +1,119 @@
+package ht16k33
+
+import "time"
+
+// spinnerFrames are the segments a spinner lights in turn, going around the
+// outside of the digit.
+//
+// spinnerFramesは、スピナーが順に点灯するセグメントで、桁の外周を回る。
+var spinnerFrames = [...]byte{segA, segB, segC, segD, segE, segF}
+
+// spinnerAnimation is the Animation started by StartSpinner.
+//
+// spinnerAnimationは、StartSpinnerで始めるAnimation。
+type spinnerAnimation struct {
+	display  int
+	position int
+	interval time.Duration
+	frame    int
+	last     uint32
+	// saved is the pattern of the digit before the spinner, shown again
+	// when it stops.
+	// savedは、スピナーの前の桁のパターンで、止めたときに再び表示する。
+	saved   byte
+	stopped bool
+}
+
+// Start shows the first frame.
+func (s *spinnerAnimation) Start(d *Device, now time.Time) {
+	s.saved = d.digitPattern(s.display, s.position)
+	s.last = tickOf(now)
+	d.setPattern(s.display, s.position, spinnerFrames[0], false)
+	d.Display()
+}
+
+// Step moves the spinner on by one segment per interval.
+func (s *spinnerAnimation) Step(d *Device, now time.Time) bool {
+	if s.stopped || tickDuration(tickOf(now)-s.last) < s.interval {
+		return false
+	}
+	s.last = tickOf(now)
+	s.frame = (s.frame + 1) % len(spinnerFrames)
+	d.setPattern(s.display, s.position, spinnerFrames[s.frame], false)
+	return true
+}
+
+// Done reports whether the spinner was stopped.
+func (s *spinnerAnimation) Done() bool {
+	return s.stopped
+}
+
+// Stop restores the content under the spinner like StopSpinner, without
+// sending it.
+func (s *spinnerAnimation) Stop(d *Device) {
+	d.stopSpinner(s.display, s.position)
+}
+
+// StartSpinner shows a loading spinner on one digit: a single segment goes
+// around the digit (a, b, c, d, e, f), moving once per interval, while the
+// other digits keep their content. It replaces a spinner already on that
+// digit. It is non-blocking: call Update from the main loop.
+//
+// StartSpinnerは、1つの桁にローディングのスピナーを表示する。1つの
+// セグメントが桁の周り(a、b、c、d、e、f)をintervalごとに回り、その他の桁は
+// 内容を保つ。その桁のスピナーを置き換える。ノンブロッキングなので、
+// メインループからUpdateを呼び出す。
+func (d *Device) StartSpinner(display int, position int, interval time.Duration) error {
+	if display < 0 || display >= d.displays || position < 0 || position >= d.digits {
+		return ErrInvalidDisplay
+	}
+	d.StopSpinner(display, position)
+	spinner := &spinnerAnimation{display: display, position: position, interval: interval}
+	d.spinners = append(d.spinners, spinner)
+	d.Animate(spinner)
+	return nil
+}
+
+// StopSpinner stops the spinner on a digit and shows the digit as it was
+// before the spinner.
+//
+// StopSpinnerは、桁のスピナーを止め、スピナーの前の桁を表示する。
+func (d *Device) StopSpinner(display int, position int) {
+	if d.stopSpinner(display, position) {
+		d.Display()
+	}
+}
+
+// stopSpinner is StopSpinner without sending the buffer. It returns true if
+// a spinner was stopped.
+//
+// stopSpinnerは、バッファを送らないStopSpinner。スピナーを止めた場合は
+// trueを返す。
+func (d *Device) stopSpinner(display int, position int) bool {
+	stopped := false
+	kept := d.spinners[:0]
+	for _, spinner := range d.spinners {
+		if spinner.display != display || spinner.position != position {
+			kept = append(kept, spinner)
+			continue
+		}
+		spinner.stopped = true
+		p := spinner.saved
+		d.setPattern(display, position, p&0x7F, p&0x80 != 0)
+		stopped = true
+	}
+	d.spinners = kept
+	return stopped
+}
+
+// IsSpinning returns true if a spinner is running on a digit.
+//
+// IsSpinningは、桁でスピナーが動いていればtrueを返す。
+func (d *Device) IsSpinning(display int, position int) bool {
+	for _, spinner := range d.spinners {
+		if spinner.display == display && spinner.position == position {
+			return true
+		}
+	}
+	return false
+}
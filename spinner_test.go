@@ -0,0 +1,33 @@
+package ht16k33
+
+import "testing"
+
+// TestStartSpinner verifies that a spinner goes around one digit and restores it when stopped.
+func TestStartSpinner(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "12.3")
+
+	if err := device.StartSpinner(0, 1, 0); err != nil {
+		t.Fatalf("StartSpinner() returned an unexpected error: %v", err)
+	}
+	for i := 0; i <= len(spinnerFrames); i++ {
+		want := spinnerFrames[i%len(spinnerFrames)]
+		if got := device.digitPattern(0, 1); got != want {
+			t.Errorf("FAIL: Frame %d is wrong!\nExpected: %x\nGot:      %x", i, want, got)
+		}
+		device.Update()
+	}
+	if !device.IsSpinning(0, 1) {
+		t.Errorf("FAIL: IsSpinning() should return true while the spinner runs")
+	}
+
+	device.StopSpinner(0, 1)
+	assertDisplay(t, device, 0, "12.3")
+	if device.IsSpinning(0, 1) || device.Update() {
+		t.Errorf("FAIL: Spinner should stop")
+	}
+
+	if err := device.StartSpinner(0, 8, 0); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: StartSpinner() with an invalid position should return ErrInvalidDisplay, got %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package ht16k33
+
+// StatusWords are the words shown for true and false by WriteStatus.
+//
+// StatusWordsは、WriteStatusでtrueとfalseに表示する言葉。
+type StatusWords struct {
+	True, False string
+}
+
+// Common status words. "CLOS" fits in four digits, like "OPEN".
+//
+// よく使う状態の言葉。"CLOS"は"OPEN"と同じく4桁に収まる。
+var (
+	OnOff      = StatusWords{True: "ON", False: "OFF"}
+	YesNo      = StatusWords{True: "YES", False: "NO"}
+	OpenClosed = StatusWords{True: "OPEN", False: "CLOS"}
+)
+
+// WriteBool shows v as "ON" or "OFF", right-aligned to the digit at pos. It
+// is WriteStatus with OnOff.
+//
+// WriteBoolは、vを"ON"または"OFF"としてposの桁に右詰めで表示する。OnOffを
+// 使ったWriteStatusと同じ。
+func (d *Device) WriteBool(display int, pos int, v bool) error {
+	return d.WriteStatus(display, pos, v, OnOff)
+}
+
+// WriteStatus shows the word for v, right-aligned so that it ends at the
+// digit at pos. It clears a field as wide as the longer of the two words
+// and leaves the rest of the display as it is, so several states can share
+// one display. If the field does not fit left of pos, the display is left
+// unchanged and ErrOverflow is returned.
+//
+// WriteStatusは、vに対応する言葉をposの桁で終わるように右詰めで表示する。
+// 2つの言葉のうち長い方の幅の欄をクリアし、ディスプレイの残りはそのまま残す
+// ので、1つのディスプレイに複数の状態を表示できる。欄がposの左に収まらない
+// 場合はディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) WriteStatus(display int, pos int, v bool, words StatusWords) error {
+	if display < 0 || display >= d.displays || pos < 0 || pos >= d.digits {
+		return ErrInvalidDisplay
+	}
+	trueCells, _, err := d.layoutString(words.True)
+	if err != nil {
+		return err
+	}
+	falseCells, _, err := d.layoutString(words.False)
+	if err != nil {
+		return err
+	}
+	width := len(trueCells)
+	if len(falseCells) > width {
+		width = len(falseCells)
+	}
+	if width > pos+1 {
+		return ErrOverflow
+	}
+	cells := falseCells
+	if v {
+		cells = trueCells
+	}
+	for p := pos - width + 1; p <= pos; p++ {
+		d.setPattern(display, p, 0, false)
+	}
+	d.writeCells(display, pos-len(cells)+1, cells)
+	d.autoDisplay()
+	return nil
+}
@@ -0,0 +1,33 @@
+package ht16k33
+
+import "testing"
+
+// TestWriteBool verifies that ON and OFF replace each other in place.
+func TestWriteBool(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "P1")
+
+	device.WriteBool(0, 7, false)
+	assertDisplay(t, device, 0, "P1   OFF")
+	device.WriteBool(0, 7, true)
+	assertDisplay(t, device, 0, "P1    ON")
+
+	if err := device.WriteBool(0, 1, true); err != ErrOverflow {
+		t.Errorf("FAIL: WriteBool() at position 1 should return ErrOverflow, got %v", err)
+	}
+	if err := device.WriteBool(0, 8, true); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: WriteBool() at position 8 should return ErrInvalidDisplay, got %v", err)
+	}
+}
+
+// TestWriteStatus verifies custom and predefined status words.
+func TestWriteStatus(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	device.WriteStatus(1, 3, true, OpenClosed)
+	device.WriteStatus(1, 7, false, YesNo)
+	assertDisplay(t, device, 1, "OPEN  NO")
+	device.WriteStatus(1, 3, false, OpenClosed)
+	device.WriteStatus(1, 7, true, YesNo)
+	assertDisplay(t, device, 1, "CLOS YES")
+}
@@ -0,0 +1,142 @@
+package ht16k33
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// ErrInvalidTemplate is returned by ParseTemplate for a layout without a
+// value field.
+var ErrInvalidTemplate = errors.New("ht16k33: invalid template")
+
+// Template is a fixed display layout with a slot for a number, parsed once
+// by ParseTemplate and then filled in by WriteTemplate every frame.
+//
+// Templateは、数値を入れる欄を持つ固定の表示レイアウト。ParseTemplateで
+// 一度だけ解析し、毎フレームWriteTemplateで値を埋める。
+type Template struct {
+	prefix, suffix string
+	intDigits      int
+	decimals       int
+}
+
+// ParseTemplate parses a layout such as "##.#°C". The value field is a run
+// of '#' with at most one '.': each '#' is a digit of the value and the '.'
+// is its decimal point. Everything before and after the field is shown as
+// is, using the font. The layout must have exactly one value field.
+//
+// ParseTemplateは、"##.#°C"のようなレイアウトを解析する。値の欄は'#'の並び
+// で、'.'を1つまで含められる。各'#'は値の1桁、'.'はその小数点になる。欄の
+// 前後はフォントを使ってそのまま表示する。レイアウトには値の欄がちょうど
+// 1つ必要。
+func ParseTemplate(layout string) (*Template, error) {
+	start := strings.IndexByte(layout, '#')
+	if start < 0 {
+		return nil, ErrInvalidTemplate
+	}
+	if start > 0 && layout[start-1] == '.' {
+		start-- // A field like ".##" has no integer digits.
+	}
+	t := &Template{prefix: layout[:start]}
+	end := start
+	point := false
+field:
+	for ; end < len(layout); end++ {
+		switch {
+		case layout[end] == '#' && point:
+			t.decimals++
+		case layout[end] == '#':
+			t.intDigits++
+		case layout[end] == '.' && !point:
+			point = true
+		default:
+			break field
+		}
+	}
+	if strings.HasSuffix(layout[:end], ".") && t.decimals == 0 {
+		end-- // A trailing '.' belongs to the suffix.
+	}
+	t.suffix = layout[end:]
+	if strings.IndexByte(t.suffix, '#') >= 0 {
+		return nil, ErrInvalidTemplate
+	}
+	return t, nil
+}
+
+// MustParseTemplate is like ParseTemplate, but panics on an invalid layout.
+// It is meant for package-level variables.
+//
+// MustParseTemplateはParseTemplateと同様だが、不正なレイアウトではpanicする。
+// パッケージレベルの変数に使うことを想定している。
+func MustParseTemplate(layout string) *Template {
+	t, err := ParseTemplate(layout)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// format returns the layout with v filled in, or false if v needs more
+// integer digits than the field has. The sign takes up one of them.
+//
+// formatは、vを埋めたレイアウトを返す。vに欄より多くの整数部の桁が必要なら
+// falseを返す。符号も整数部の1桁を使う。
+func (t *Template) format(v float64, padding Padding, rounding RoundingMode) (string, bool) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "", false
+	}
+	text := trimNegativeZero(formatRounded(v, t.decimals, rounding))
+	sign := ""
+	if text[0] == '-' {
+		sign, text = "-", text[1:]
+	}
+	intPart, fracPart := text, ""
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		intPart, fracPart = text[:i], text[i:]
+	}
+	if intPart == "0" && t.intDigits == len(sign) {
+		intPart = ""
+	}
+	n := t.intDigits - len(sign) - len(intPart)
+	if n < 0 {
+		return "", false
+	}
+	if padding == PadZero {
+		intPart = sign + strings.Repeat("0", n) + intPart
+	} else {
+		intPart = strings.Repeat(" ", n) + sign + intPart
+	}
+	return t.prefix + intPart + fracPart + t.suffix, true
+}
+
+// WriteTemplate clears the display and writes the template from the left
+// with v filled in, rounded to the digits after the decimal point of the
+// field. The layout is fixed, so the Alignment does not move it. If v does
+// not fit in the field, the display is left unchanged and ErrOverflow is
+// returned. Like WriteString, it returns ErrTruncated if the layout is
+// longer than the display.
+//
+// WriteTemplateは、ディスプレイをクリアし、vを埋めたテンプレートを左から
+// 書き込む。vは欄の小数点以下の桁数に丸める。レイアウトは固定なので、
+// Alignmentでは動かない。vが欄に収まらない場合はディスプレイを変更せずに
+// ErrOverflowを返す。WriteStringと同様に、レイアウトがディスプレイより
+// 長ければErrTruncatedを返す。
+func (d *Device) WriteTemplate(display int, t *Template, v float64) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	text, ok := t.format(v, d.padding, d.rounding)
+	if !ok {
+		return ErrOverflow
+	}
+	cells, total, err := d.layoutString(text)
+	if err != nil {
+		return err
+	}
+	d.clearDisplay(display)
+	shown := d.writeCells(display, 0, cells)
+	d.autoDisplay()
+	_, err = consumed(cells, shown, total)
+	return err
+}
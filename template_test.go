@@ -0,0 +1,47 @@
+package ht16k33
+
+import "testing"
+
+// TestParseTemplate verifies that layouts without a single value field are rejected.
+func TestParseTemplate(t *testing.T) {
+	for _, layout := range []string{"", "C", "##.#-##"} {
+		if _, err := ParseTemplate(layout); err != ErrInvalidTemplate {
+			t.Errorf("FAIL: ParseTemplate(%q) should return ErrInvalidTemplate, got %v", layout, err)
+		}
+	}
+}
+
+// TestWriteTemplate verifies that values are slotted into the layout.
+func TestWriteTemplate(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	temperature := MustParseTemplate("t ##.#°C")
+
+	tests := []struct {
+		v        float64
+		expected string
+	}{
+		{23.46, "t 23.5°C"},
+		{-5, "t -5.0°C"},
+		{0.04, "t  0.0°C"},
+	}
+	for _, test := range tests {
+		if err := device.WriteTemplate(0, temperature, test.v); err != nil {
+			t.Fatalf("WriteTemplate(%v) returned an unexpected error: %v", test.v, err)
+		}
+		assertDisplay(t, device, 0, test.expected)
+	}
+
+	if err := device.WriteTemplate(0, temperature, 123); err != ErrOverflow {
+		t.Errorf("FAIL: WriteTemplate(123) should return ErrOverflow, got %v", err)
+	}
+	assertDisplay(t, device, 0, "t  0.0°C")
+
+	device.SetPadding(PadZero)
+	device.WriteTemplate(1, MustParseTemplate("P ###"), 7)
+	assertDisplay(t, device, 1, "P 007")
+
+	// The layout is fixed, so the alignment does not move it.
+	device.SetAlignment(AlignRight)
+	device.WriteTemplate(1, MustParseTemplate("P ###"), 8)
+	assertDisplay(t, device, 1, "P 008")
+}
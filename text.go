@@ -0,0 +1,525 @@
+package ht16k33
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ErrTruncated is returned when a string does not fit on the display and
+// its remaining characters were dropped.
+var ErrTruncated = errors.New("ht16k33: string truncated")
+
+// UnknownRunePolicy decides how characters that are not in the font are
+// rendered.
+//
+// UnknownRunePolicyは、フォントにない文字の描画方法を決める。
+type UnknownRunePolicy uint8
+
+const (
+	// UnknownBlank renders an unknown character as a blank digit (default).
+	UnknownBlank UnknownRunePolicy = iota
+	// UnknownSkip drops an unknown character without using a digit.
+	UnknownSkip
+	// UnknownFallback renders an unknown character with the fallback glyph
+	// (an underscore unless changed with SetFallbackGlyph).
+	UnknownFallback
+	// UnknownError renders nothing and returns an *UnknownRuneError.
+	UnknownError
+)
+
+// UnknownRuneError is returned under the UnknownError policy and lists the
+// characters that are not in the font.
+//
+// UnknownRuneErrorは、UnknownErrorポリシーの場合に返され、フォントにない
+// 文字を列挙する。
+type UnknownRuneError struct {
+	Runes []rune
+}
+
+func (e *UnknownRuneError) Error() string {
+	msg := "ht16k33: characters not in font:"
+	for _, r := range e.Runes {
+		msg += " " + strconv.QuoteRune(r)
+	}
+	return msg
+}
+
+// SetUnknownRunePolicy sets how characters that are not in the font are
+// rendered by every method that takes characters.
+//
+// SetUnknownRunePolicyは、文字を受け取るすべてのメソッドで、フォントにない
+// 文字をどう描画するかを設定する。
+func (d *Device) SetUnknownRunePolicy(policy UnknownRunePolicy) {
+	d.unknownRunes = policy
+}
+
+// SetFallbackGlyph sets the segment pattern used by the UnknownFallback policy.
+//
+// SetFallbackGlyphは、UnknownFallbackポリシーで使うセグメントパターンを設定する。
+func (d *Device) SetFallbackGlyph(pattern byte) {
+	d.fallbackGlyph = pattern
+}
+
+// SetDecimalComma makes the string writers treat ',' as a decimal point,
+// like '.', for numbers formatted in the European style (e.g. "12,5").
+//
+// SetDecimalCommaを有効にすると、文字列を書き込むメソッドは','を'.'と同じ
+// ように小数点として扱う。ヨーロッパ式の数値(例: "12,5")に使う。
+func (d *Device) SetDecimalComma(enabled bool) {
+	d.decimalComma = enabled
+}
+
+// ColonPolicy decides how the string writers render ':' (e.g. "12:34").
+//
+// ColonPolicyは、文字列を書き込むメソッドが':'(例: "12:34")をどう描画するか
+// を決める。
+type ColonPolicy uint8
+
+const (
+	// ColonUnknown renders ':' like any other character, following the
+	// UnknownRunePolicy unless the font has a glyph for it (default).
+	ColonUnknown ColonPolicy = iota
+	// ColonDot lights up the decimal point of the preceding digit.
+	ColonDot
+	// ColonBlank renders ':' as a blank digit.
+	ColonBlank
+	// ColonOutput lights up the dedicated colon LED set with SetColonOutput
+	// without using a digit.
+	ColonOutput
+)
+
+// colonOutput is the location of a dedicated colon LED.
+//
+// colonOutputは、専用のコロンLEDの位置。
+type colonOutput struct {
+	position int
+	seg      Segment
+	ok       bool
+}
+
+// SetColonPolicy sets how ':' is rendered by the string writers.
+//
+// SetColonPolicyは、文字列を書き込むメソッドで':'をどう描画するかを設定する。
+func (d *Device) SetColonPolicy(policy ColonPolicy) {
+	d.colonPolicy = policy
+}
+
+// SetColonOutput tells the Device that a display has a dedicated colon LED,
+// wired to the segment row seg and the COM line position. It is usually on a
+// COM line that is not used by a digit (see WithGeometry). The LED is lit
+// under the ColonOutput policy and turned off when the display is cleared.
+//
+// SetColonOutputは、ディスプレイに専用のコロンLEDがあり、セグメントの行seg
+// とCOMのpositionに接続されていることをDeviceに伝える。通常は桁に使われて
+// いないCOMにある(WithGeometryを参照)。ColonOutputポリシーの場合に点灯し、
+// ディスプレイをクリアすると消灯する。
+func (d *Device) SetColonOutput(display int, position int, seg Segment) {
+	if display < 0 || display >= d.displays || position < 0 || position >= MaxDigitsPerDisplay || seg > SegmentDP {
+		return
+	}
+	d.colons[display] = colonOutput{position: position, seg: seg, ok: true}
+}
+
+// setColon turns the colon LED of a display on or off, if it has one.
+//
+// setColonは、ディスプレイにコロンLEDがあれば点灯または消灯する。
+func (d *Device) setColon(display int, on bool) {
+	colon := d.colons[display]
+	if !colon.ok {
+		return
+	}
+	row := display*segmentRows + int(colon.seg)
+	if on {
+		d.buffer[row] |= 1 << colon.position
+	} else {
+		d.buffer[row] &^= 1 << colon.position
+	}
+}
+
+// isDecimalPoint returns true if r is rendered as a decimal point.
+//
+// isDecimalPointは、rを小数点として描画する場合にtrueを返す。
+func (d *Device) isDecimalPoint(r rune) bool {
+	return r == '.' || (r == ',' && d.decimalComma)
+}
+
+// resolveRune looks r up in the font and applies the unknown-rune policy.
+// skip is true when r should not take up a digit.
+//
+// resolveRuneは、フォントからrを探し、未知の文字のポリシーを適用する。
+// rが桁を使わない場合はskipがtrueになる。
+func (d *Device) resolveRune(r rune) (pattern byte, skip bool, err error) {
+	if pattern, ok := d.font.Glyph(r); ok {
+		return pattern, false, nil
+	}
+	switch d.unknownRunes {
+	case UnknownSkip:
+		return 0, true, nil
+	case UnknownFallback:
+		return d.fallbackGlyph, false, nil
+	case UnknownError:
+		return 0, true, &UnknownRuneError{Runes: []rune{r}}
+	default:
+		return 0, false, nil
+	}
+}
+
+// cell is one digit of rendered text: a segment pattern and its decimal point.
+//
+// cellは、描画するテキストの1桁分。セグメントパターンと小数点。
+type cell struct {
+	pattern byte
+	dot     bool
+	// colon lights up the colon LED of the display this cell is placed on.
+	// colonは、このセルを配置したディスプレイのコロンLEDを点灯させる。
+	colon bool
+	// end is the number of runes of the source string consumed up to and
+	// including this cell.
+	// endは、このセルまでに消費した元の文字列のルーン数。
+	end int
+}
+
+// layoutString converts s into digit cells using the font of the Device,
+// and also returns the number of runes in s.
+// A '.' following a character lights up the decimal point of that digit,
+// and any other '.' is a blank digit with its decimal point lit. With
+// SetDecimalComma, ',' is handled the same way. ':' follows the ColonPolicy.
+// Characters that are not in the font follow the UnknownRunePolicy; under
+// UnknownError no cells are returned.
+//
+// layoutStringは、Deviceのフォントを使ってsを桁ごとのセルに変換し、
+// sのルーン数も返す。
+// 文字の後の'.'はその桁の小数点を点灯させ、それ以外の'.'は小数点だけが
+// 点灯した空白の桁になる。SetDecimalCommaを有効にすると','も同様に扱う。
+// ':'はColonPolicyに従う。
+// フォントにない文字はUnknownRunePolicyに従う。
+// UnknownErrorの場合はセルを返さない。
+func (d *Device) layoutString(s string) ([]cell, int, error) {
+	cells := make([]cell, 0, len(s))
+	runes := []rune(s) // runeのスライスに変換して、マルチバイト文字にも対応する
+	var unknown []rune
+	for i := 0; i < len(runes); i++ {
+		if d.isDecimalPoint(runes[i]) {
+			cells = append(cells, cell{dot: true, end: i + 1})
+			continue
+		}
+		if runes[i] == ':' && d.colonPolicy != ColonUnknown {
+			last := len(cells) - 1
+			switch d.colonPolicy {
+			case ColonDot:
+				if last >= 0 && !cells[last].dot {
+					cells[last].dot = true
+					cells[last].end = i + 1
+				} else {
+					cells = append(cells, cell{dot: true, end: i + 1})
+				}
+			case ColonBlank:
+				cells = append(cells, cell{end: i + 1})
+			case ColonOutput:
+				if last >= 0 {
+					cells[last].colon = true
+					cells[last].end = i + 1
+				}
+			}
+			continue
+		}
+		pattern, skip, err := d.resolveRune(runes[i])
+		if err != nil {
+			unknown = append(unknown, runes[i])
+		}
+		if skip {
+			continue
+		}
+		dot := false
+		// Look ahead for a dot
+		if i+1 < len(runes) && d.isDecimalPoint(runes[i+1]) {
+			dot = true
+			i++ // ドットを処理したので、次の文字はスキップ
+		}
+		cells = append(cells, cell{pattern: pattern, dot: dot, end: i + 1})
+	}
+	if unknown != nil {
+		return nil, 0, &UnknownRuneError{Runes: unknown}
+	}
+	return cells, len(runes), nil
+}
+
+// consumed returns the number of runes rendered when only the first shown
+// cells fit, and ErrTruncated if some cells were dropped.
+//
+// consumedは、先頭のshown個のセルだけが収まった場合に描画されたルーン数を
+// 返す。捨てられたセルがあればErrTruncatedを返す。
+func consumed(cells []cell, shown int, total int) (int, error) {
+	if shown >= len(cells) {
+		return total, nil
+	}
+	if shown <= 0 {
+		return 0, ErrTruncated
+	}
+	return cells[shown-1].end, ErrTruncated
+}
+
+// writeCells places cells on a display starting at startPos, and returns
+// the number of cells that fit. Cells that do not fit are dropped.
+//
+// writeCellsは、ディスプレイのstartPosの位置からセルを配置し、収まった
+// セルの数を返す。収まらないセルは捨てられる。
+func (d *Device) writeCells(display int, startPos int, cells []cell) int {
+	shown := 0
+	for _, c := range cells {
+		if startPos+shown >= d.digits {
+			break
+		}
+		d.placeCell(display, startPos+shown, c)
+		shown++
+	}
+	return shown
+}
+
+// combinedDisplay is used internally as the display number of the two
+// displays treated as a single 16-digit display.
+//
+// combinedDisplayは、2つのディスプレイを1つの16桁ディスプレイとして扱う
+// 場合に内部で使うディスプレイ番号。
+const combinedDisplay = -1
+
+// width returns the number of digits of a display, or of both displays for
+// combinedDisplay.
+//
+// widthは、ディスプレイの桁数を返す。combinedDisplayなら両方の桁数を返す。
+func (d *Device) width(display int) int {
+	if display == combinedDisplay {
+		return d.digits * d.displays
+	}
+	return d.digits
+}
+
+// clearSurface clears a display, or both displays for combinedDisplay.
+//
+// clearSurfaceは、ディスプレイをクリアする。combinedDisplayなら両方をクリア
+// する。
+func (d *Device) clearSurface(display int) {
+	if display == combinedDisplay {
+		for display := 0; display < d.displays; display++ {
+			d.clearDisplay(display)
+		}
+		return
+	}
+	d.clearDisplay(display)
+}
+
+// placeOn sets one cell at a position of a display, or at a position from 0
+// to 15 across both displays for combinedDisplay.
+//
+// placeOnは、ディスプレイの指定した位置に1つのセルを設定する。
+// combinedDisplayなら両方のディスプレイにまたがる0から15の位置に設定する。
+func (d *Device) placeOn(display int, position int, c cell) {
+	if display == combinedDisplay {
+		display, position = position/d.digits, position%d.digits
+	}
+	d.placeCell(display, position, c)
+}
+
+// placeCell sets one cell at a position, including its colon.
+//
+// placeCellは、指定した位置に1つのセルをコロンも含めて設定する。
+func (d *Device) placeCell(display int, position int, c cell) {
+	d.setPattern(display, position, c.pattern, c.dot)
+	if c.colon {
+		d.setColon(display, true)
+	}
+}
+
+// Alignment decides where WriteString and WriteString16 put strings that are
+// shorter than the display.
+//
+// Alignmentは、ディスプレイより短い文字列をWriteStringとWriteString16がどこ
+// に置くかを決める。
+type Alignment uint8
+
+const (
+	// AlignLeft starts strings at the leftmost digit (default).
+	AlignLeft Alignment = iota
+	// AlignCenter centers strings, leaning left if they cannot be exactly
+	// centered.
+	AlignCenter
+	// AlignRight ends strings at the rightmost digit.
+	AlignRight
+)
+
+// SetAlignment sets where WriteString and WriteString16 put strings that are
+// shorter than the display. Strings that do not fit always start at the
+// leftmost digit.
+//
+// SetAlignmentは、ディスプレイより短い文字列をWriteStringとWriteString16が
+// どこに置くかを設定する。収まらない文字列は常に左端から始まる。
+func (d *Device) SetAlignment(alignment Alignment) {
+	d.alignment = alignment
+}
+
+// writeAligned clears the display and writes cells by the Alignment,
+// returning how many of them fit.
+//
+// writeAlignedは、ディスプレイをクリアし、Alignmentに従ってcellsを書き込み、
+// 収まった数を返す。
+func (d *Device) writeAligned(display int, cells []cell) int {
+	d.clearSurface(display)
+	width := d.width(display)
+	start := 0
+	if len(cells) < width {
+		switch d.alignment {
+		case AlignCenter:
+			start = (width - len(cells)) / 2
+		case AlignRight:
+			start = width - len(cells)
+		}
+	}
+	shown := 0
+	for _, c := range cells {
+		if start+shown >= width {
+			break
+		}
+		d.placeOn(display, start+shown, c)
+		shown++
+	}
+	return shown
+}
+
+// WriteString displays a string on one of the two displays.
+// It clears the target display before writing, and places the string by
+// the Alignment (left by default).
+// It returns the number of characters of s that were rendered, and
+// ErrTruncated if the rest did not fit on the display. Under the
+// UnknownError policy the display is left unchanged if s has characters
+// that are not in the font.
+//
+// WriteStringは、2つのディスプレイのいずれかに文字列を表示する。
+// 書き込む前にディスプレイをクリアし、Alignment(デフォルトは左寄せ)に
+// 従って文字列を置く。
+// 描画できたsの文字数を返し、残りが収まらなかった場合はErrTruncatedを返す。
+// UnknownErrorポリシーの場合、sにフォントにない文字があればディスプレイは
+// 変更しない。
+//
+// display: 0 for the first display (A), 1 for the second (B).
+// s: The string to display. Handles numbers and dots (e.g., "123", "45.6", "78.").
+func (d *Device) WriteString(display int, s string) (int, error) {
+	if display < 0 || display >= d.displays {
+		return 0, ErrInvalidDisplay
+	}
+
+	cells, total, err := d.layoutString(s)
+	if err != nil {
+		return 0, err
+	}
+	shown := d.writeAligned(display, cells)
+	d.autoDisplay()
+	return consumed(cells, shown, total)
+}
+
+// WriteStringAt is like WriteString, but starts at startPos and only
+// changes the digits it writes, so the rest of the display keeps its content.
+//
+// WriteStringAtはWriteStringと同様だが、startPosの位置から書き始め、
+// 書き込んだ桁だけを変更するので、ディスプレイの残りの部分はそのまま残る。
+//
+// display: 0 for the first display (A), 1 for the second (B).
+// startPos: 0-7, the position of the first character.
+// s: The string to display.
+func (d *Device) WriteStringAt(display int, startPos int, s string) (int, error) {
+	if display < 0 || display >= d.displays || startPos < 0 || startPos >= d.digits {
+		return 0, ErrInvalidDisplay
+	}
+
+	cells, total, err := d.layoutString(s)
+	if err != nil {
+		return 0, err
+	}
+	shown := d.writeCells(display, startPos, cells)
+	d.autoDisplay()
+	return consumed(cells, shown, total)
+}
+
+// WriteString16 treats the two 8-digit displays as a single 16-digit display
+// and writes a string across it. Dots are handled the same way as in
+// WriteString. Both displays are cleared before writing.
+// It returns the number of characters of s that were rendered, and
+// ErrTruncated if the rest did not fit.
+//
+// WriteString16は、2つの8桁ディスプレイを1つの16桁ディスプレイとして扱い、
+// 文字列を書き込む。ドットはWriteStringと同じように扱う。
+// 書き込む前に両方のディスプレイをクリアする。
+// 描画できたsの文字数を返し、残りが収まらなかった場合はErrTruncatedを返す。
+func (d *Device) WriteString16(s string) (int, error) {
+	cells, total, err := d.layoutString(s)
+	if err != nil {
+		return 0, err
+	}
+	shown := d.writeAligned(combinedDisplay, cells)
+	d.autoDisplay()
+	return consumed(cells, shown, total)
+}
+
+// maxLabelDigits is the most digits a WriteKV label may take up.
+const maxLabelDigits = 3
+
+// WriteKV clears the display and shows a label of up to three digits on the
+// left and a value right-aligned on the remaining digits, such as
+// "SPd  128". If the label is longer or the value does not fit, the display
+// is left unchanged and ErrOverflow is returned.
+//
+// WriteKVは、ディスプレイをクリアし、左に3桁までのラベル、残りの桁に右詰め
+// で値を表示する("SPd  128"など)。ラベルがそれより長いか値が収まらない
+// 場合は、ディスプレイを変更せずにErrOverflowを返す。
+func (d *Device) WriteKV(display int, label string, value string) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	labelCells, _, err := d.layoutString(label)
+	if err != nil {
+		return err
+	}
+	valueCells, _, err := d.layoutString(value)
+	if err != nil {
+		return err
+	}
+	if len(labelCells) > maxLabelDigits || len(labelCells)+len(valueCells) > d.digits {
+		return ErrOverflow
+	}
+	d.clearDisplay(display)
+	d.writeCells(display, 0, labelCells)
+	d.writeCells(display, d.digits-len(valueCells), valueCells)
+	d.autoDisplay()
+	return nil
+}
+
+// Writer returns an io.Writer that shows what is written to it on a display,
+// so formatting code such as fmt.Fprintf(d.Writer(0), "%5.1f", v) can be
+// used directly. Each Write replaces the display content with the last line
+// of the written data, truncated to the width of the display.
+//
+// Writerは、書き込まれた内容をディスプレイに表示するio.Writerを返す。
+// fmt.Fprintf(d.Writer(0), "%5.1f", v)のような整形のコードをそのまま使える。
+// Writeのたびに、書き込まれたデータの最後の行でディスプレイの内容を置き換え、
+// ディスプレイの幅に収まらない部分は切り捨てる。
+func (d *Device) Writer(display int) io.Writer {
+	return displayWriter{d: d, display: display}
+}
+
+// displayWriter is the io.Writer returned by Device.Writer.
+type displayWriter struct {
+	d       *Device
+	display int
+}
+
+func (w displayWriter) Write(p []byte) (int, error) {
+	s := strings.TrimRight(string(p), "\r\n")
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	if _, err := w.d.WriteString(w.display, s); err != nil && err != ErrTruncated {
+		return 0, err
+	}
+	return len(p), nil
+}
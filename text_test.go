@@ -0,0 +1,257 @@
+package ht16k33
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// TestWriteStringAt verifies that only the written digits change.
+func TestWriteStringAt(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "88888888")
+
+	device.WriteStringAt(0, 6, "1.23")
+
+	expectedDevice := newTestDevice(t, &mockI2C{})
+	expectedDevice.WriteString(0, "8888881.2")
+	if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+	}
+}
+
+// TestWriteString16 verifies that a string flows from display A into display B.
+func TestWriteString16(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString16("12345678.9012345.6")
+
+	expectedDevice := newTestDevice(t, &mockI2C{})
+	expectedDevice.WriteString(0, "12345678.")
+	expectedDevice.WriteString(1, "9012345.6")
+	if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+	}
+}
+
+// TestWriteStringTruncation verifies the count and error for strings that do not fit.
+func TestWriteStringTruncation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		s           string
+		expectedN   int
+		expectedErr error
+	}{
+		{name: "Fits", s: "1234.5678.", expectedN: 10},
+		{name: "Unknown characters are blank", s: "1234567~", expectedN: 8},
+		{name: "Truncated", s: "123456789", expectedN: 8, expectedErr: ErrTruncated},
+		{name: "Truncated after a dot", s: "1234567.8.9", expectedN: 10, expectedErr: ErrTruncated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := newTestDevice(t, &mockI2C{})
+			n, err := device.WriteString(0, tc.s)
+			if n != tc.expectedN || err != tc.expectedErr {
+				t.Errorf("FAIL: WriteString(%q) = %d, %v; expected %d, %v", tc.s, n, err, tc.expectedN, tc.expectedErr)
+			}
+		})
+	}
+
+	device := newTestDevice(t, &mockI2C{})
+	if _, err := device.WriteString(2, "1"); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: WriteString() on display 2 should return ErrInvalidDisplay, got %v", err)
+	}
+	if n, err := device.WriteStringAt(0, 6, "123"); n != 2 || err != ErrTruncated {
+		t.Errorf("FAIL: WriteStringAt() = %d, %v; expected 2, ErrTruncated", n, err)
+	}
+	if n, err := device.WriteString16("12345678901234567"); n != 16 || err != ErrTruncated {
+		t.Errorf("FAIL: WriteString16() = %d, %v; expected 16, ErrTruncated", n, err)
+	}
+}
+
+// TestUnknownRunePolicy verifies each way of rendering characters missing from the font.
+func TestUnknownRunePolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   UnknownRunePolicy
+		expected string // Equivalent string using known characters only
+	}{
+		{name: "Blank", policy: UnknownBlank, expected: "1 2. 3"},
+		{name: "Skip", policy: UnknownSkip, expected: "12.3"},
+		{name: "Fallback", policy: UnknownFallback, expected: "1-2.-3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := newTestDevice(t, &mockI2C{})
+			device.SetUnknownRunePolicy(tc.policy)
+			device.SetFallbackGlyph(segG)
+			if _, err := device.WriteString(0, "1~2.~3"); err != nil {
+				t.Fatalf("WriteString() returned an unexpected error: %v", err)
+			}
+
+			expectedDevice := newTestDevice(t, &mockI2C{})
+			expectedDevice.WriteString(0, tc.expected)
+			if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+				t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+			}
+		})
+	}
+
+	t.Run("Error", func(t *testing.T) {
+		device := newTestDevice(t, &mockI2C{})
+		device.SetUnknownRunePolicy(UnknownError)
+		device.WriteString(0, "8")
+		before := device.buffer
+
+		_, err := device.WriteString(0, "1~2@")
+		unknownErr, ok := err.(*UnknownRuneError)
+		if !ok || string(unknownErr.Runes) != "~@" {
+			t.Fatalf("FAIL: WriteString() should return an UnknownRuneError for \"~@\", got %v", err)
+		}
+		if err := device.SetDigitOnDisplay(0, 1, '~', false); err == nil {
+			t.Errorf("FAIL: SetDigitOnDisplay() should return an error for an unknown character")
+		}
+		if device.buffer != before {
+			t.Errorf("FAIL: Buffer must not change when an error is returned")
+		}
+	})
+}
+
+// TestDecimalComma verifies that ',' can be used as a decimal separator.
+func TestDecimalComma(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.SetDecimalComma(true)
+	device.WriteString(0, "1.234,5")
+
+	expectedDevice := newTestDevice(t, &mockI2C{})
+	expectedDevice.WriteString(0, "1.234.5")
+	if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+	}
+
+	// Without the option, ',' is an unknown character (blank by default).
+	device.SetDecimalComma(false)
+	device.WriteString(0, "1,5")
+	expectedDevice.WriteString(0, "1 5")
+	if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+	}
+}
+
+// TestColonPolicy verifies each way of rendering ':'.
+func TestColonPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   ColonPolicy
+		expected string // Equivalent string without ':'
+	}{
+		{name: "Unknown", policy: ColonUnknown, expected: "12 34"},
+		{name: "Dot", policy: ColonDot, expected: "12.34"},
+		{name: "Blank", policy: ColonBlank, expected: "12 34"},
+		{name: "Output without a colon LED", policy: ColonOutput, expected: "1234"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			device := newTestDevice(t, &mockI2C{})
+			device.SetColonPolicy(tc.policy)
+			device.WriteString(0, "12:34")
+
+			expectedDevice := newTestDevice(t, &mockI2C{})
+			expectedDevice.WriteString(0, tc.expected)
+			if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+				t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+			}
+		})
+	}
+
+	t.Run("Output", func(t *testing.T) {
+		// Two 4-digit displays with the colon LED on COM 4, segment a.
+		device, err := New(&mockI2C{}, 0x70, WithGeometry(2, 4))
+		if err != nil {
+			t.Fatalf("New() returned an unexpected error: %v", err)
+		}
+		device.SetColonPolicy(ColonOutput)
+		device.SetColonOutput(1, 4, SegmentA)
+
+		device.WriteString(1, "12:34")
+		if device.buffer[8]&(1<<4) == 0 {
+			t.Errorf("FAIL: Colon LED should be lit after writing \"12:34\"")
+		}
+		device.WriteString(1, "1234")
+		if device.buffer[8]&(1<<4) != 0 {
+			t.Errorf("FAIL: Colon LED should be off after writing \"1234\"")
+		}
+	})
+}
+
+// TestWriter verifies that formatted output lands on the display.
+func TestWriter(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	w := device.Writer(1)
+
+	fmt.Fprintf(w, "%5.1f", 21.45)
+	expectedDevice := newTestDevice(t, &mockI2C{})
+	expectedDevice.WriteString(1, " 21.4")
+	if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+	}
+
+	// Only the last line is shown, truncated to 8 digits.
+	n, err := fmt.Fprintln(w, "old\n123456789")
+	if n != 14 || err != nil {
+		t.Errorf("FAIL: Write() = %d, %v; expected 14, nil", n, err)
+	}
+	expectedDevice.WriteString(1, "12345678")
+	if !bytes.Equal(device.buffer[:], expectedDevice.buffer[:]) {
+		t.Errorf("FAIL: Buffer content is wrong!\nExpected: %x\nGot:      %x", expectedDevice.buffer[:], device.buffer[:])
+	}
+
+	if _, err := fmt.Fprint(device.Writer(2), "1"); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: Writing to display 2 should return ErrInvalidDisplay, got %v", err)
+	}
+}
+
+// TestSetAlignment verifies centered and right-aligned strings.
+func TestSetAlignment(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	device.SetAlignment(AlignCenter)
+	device.WriteString(0, "ON")
+	assertDisplay(t, device, 0, "   ON")
+	device.WriteString(1, "OFF")
+	assertDisplay(t, device, 1, "  OFF")
+
+	device.SetAlignment(AlignRight)
+	device.WriteString(0, "1.5")
+	assertDisplay(t, device, 0, "      1.5")
+	if n, err := device.WriteString(1, "123456789"); n != 8 || err != ErrTruncated {
+		t.Errorf("FAIL: WriteString() of a long string should return 8, ErrTruncated, got %d, %v", n, err)
+	}
+	assertDisplay(t, device, 1, "12345678")
+
+	device.WriteString16("END")
+	assertDisplay(t, device, 0, "")
+	assertDisplay(t, device, 1, "     END")
+}
+
+// TestWriteKV verifies the label and value layout.
+func TestWriteKV(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	if err := device.WriteKV(0, "SPd", "128"); err != nil {
+		t.Fatalf("WriteKV() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "SPd  128")
+	device.WriteKV(1, "U", "3.30")
+	assertDisplay(t, device, 1, "U    3.30")
+
+	if err := device.WriteKV(0, "SPEED", "1"); err != ErrOverflow {
+		t.Errorf("FAIL: WriteKV() with a long label should return ErrOverflow, got %v", err)
+	}
+	if err := device.WriteKV(0, "SPd", "123456"); err != ErrOverflow {
+		t.Errorf("FAIL: WriteKV() with a long value should return ErrOverflow, got %v", err)
+	}
+	assertDisplay(t, device, 0, "SPd  128")
+}
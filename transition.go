@@ -0,0 +1,635 @@
+package ht16k33
+
+import (
+	"math/rand"
+	"time"
+)
+
+// TransitionDirection is the direction in which a transition moves across
+// the digits.
+//
+// TransitionDirectionは、トランジションが桁の上を進む方向。
+type TransitionDirection uint8
+
+const (
+	// TransitionLeftToRight moves from the leftmost digit to the rightmost.
+	TransitionLeftToRight TransitionDirection = iota
+	// TransitionRightToLeft moves from the rightmost digit to the leftmost.
+	TransitionRightToLeft
+)
+
+// transition is the common part of the animations that change a display
+// from its current content to new content frame by frame.
+//
+// transitionは、ディスプレイを現在の内容から新しい内容へフレームごとに
+// 変えるアニメーションの共通部分。
+type transition struct {
+	display  int
+	from, to []byte
+	frame    int
+	frames   int
+	delay    time.Duration
+	last     uint32
+	done     bool
+	// separators are the separators of the new content, as WriteTime marks
+	// them.
+	// separatorsは、WriteTimeが記録するような、新しい内容の区切り。
+	separators [16]byte
+}
+
+// Start begins the transition at its first frame.
+func (t *transition) Start(d *Device, now time.Time) {
+	t.last = tickOf(now)
+}
+
+// Done reports whether the transition has finished or was replaced.
+func (t *transition) Done() bool {
+	return t.done
+}
+
+// Stop shows the new content at once.
+func (t *transition) Stop(d *Device) {
+	if !t.done {
+		t.done = true
+		t.draw(d, nil)
+	}
+}
+
+// advance moves to the next frame once the delay has passed, and reports
+// whether it did.
+//
+// advanceは、delayが経過したら次のフレームへ進み、進んだかを返す。
+func (t *transition) advance(now time.Time) bool {
+	if t.done || tickDuration(tickOf(now)-t.last) < t.delay {
+		return false
+	}
+	t.last = tickOf(now)
+	t.frame++
+	if t.frame >= t.frames {
+		t.done = true
+	}
+	return true
+}
+
+// draw sets every digit of the surface to pattern(pos), or to the new
+// content once the transition has finished.
+//
+// drawは、表示面のすべての桁をpattern(pos)にする。トランジションが終わって
+// いれば新しい内容にする。
+func (t *transition) draw(d *Device, pattern func(pos int) byte) {
+	if t.done {
+		d.clearSurface(t.display)
+	}
+	for pos := range t.to {
+		p := t.to[pos]
+		if !t.done {
+			p = pattern(pos)
+		}
+		d.setSurfacePattern(t.display, pos, p)
+	}
+	if t.done {
+		for row := range d.separators {
+			if t.display == combinedDisplay || row/segmentRows == t.display {
+				d.separators[row] = t.separators[row]
+			}
+		}
+	}
+}
+
+// newTransition lays out s on a display (or on both displays for
+// combinedDisplay) as WriteString does, and returns a transition from the
+// current content to it. The buffer is left unchanged.
+//
+// newTransitionは、WriteStringと同様にsをディスプレイ(combinedDisplayなら
+// 両方)に配置し、現在の内容からそれへのトランジションを返す。バッファは
+// 変更しない。
+func (d *Device) newTransition(display int, s string, frames int, delay time.Duration) (*transition, error) {
+	if display != combinedDisplay && (display < 0 || display >= d.displays) {
+		return nil, ErrInvalidDisplay
+	}
+	cells, _, err := d.layoutString(s)
+	if err != nil {
+		return nil, err
+	}
+	return d.captureTransition(display, frames, delay, func() error {
+		d.writeAligned(display, cells)
+		return nil
+	})
+}
+
+// captureTransition returns a transition from the current content of a
+// display to what write leaves in the buffer. The buffer is restored after
+// write, which is called with autoFlush off.
+//
+// captureTransitionは、ディスプレイの現在の内容からwriteがバッファに残す
+// 内容へのトランジションを返す。バッファはwriteの後に元に戻し、writeは
+// autoFlushを無効にして呼び出す。
+func (d *Device) captureTransition(display int, frames int, delay time.Duration, write func() error) (*transition, error) {
+	t := &transition{display: display, frames: frames, delay: delay}
+	t.from = d.surfacePatterns(display)
+	buffer, separators, autoFlush := d.buffer, d.separators, d.autoFlush
+	d.autoFlush = false
+	err := write()
+	t.to = d.surfacePatterns(display)
+	t.separators = d.separators
+	d.buffer, d.separators, d.autoFlush = buffer, separators, autoFlush
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// startTransition replaces the transition running on the display of t, and
+// starts animation, which draws t.
+//
+// startTransitionは、tのディスプレイで動いているトランジションを置き換え、
+// tを描くanimationを開始する。
+func (d *Device) startTransition(t *transition, animation Animation) {
+	for i, running := range d.transitions {
+		if running != nil && (t.display == combinedDisplay || running.display == combinedDisplay || running.display == t.display) {
+			running.done = true
+			d.transitions[i] = nil
+		}
+	}
+	slot := t.display
+	if slot == combinedDisplay {
+		slot = 0
+	}
+	d.transitions[slot] = t
+	d.Animate(animation)
+}
+
+// surfacePatterns returns the patterns of all digits of a display, or of
+// both displays for combinedDisplay, with the decimal point in bit 7.
+//
+// surfacePatternsは、ディスプレイ(combinedDisplayなら両方)のすべての桁の
+// パターンを返す。小数点はビット7になる。
+func (d *Device) surfacePatterns(display int) []byte {
+	patterns := make([]byte, d.width(display))
+	for pos := range patterns {
+		if display == combinedDisplay {
+			patterns[pos] = d.digitPattern(pos/d.digits, pos%d.digits)
+		} else {
+			patterns[pos] = d.digitPattern(display, pos)
+		}
+	}
+	return patterns
+}
+
+// setSurfacePattern sets a pattern with the decimal point in bit 7 at a
+// position of a display, or across both displays for combinedDisplay.
+//
+// setSurfacePatternは、小数点をビット7に持つパターンをディスプレイ
+// (combinedDisplayなら両方にまたがる)の指定した位置に設定する。
+func (d *Device) setSurfacePattern(display int, position int, pattern byte) {
+	if display == combinedDisplay {
+		display, position = position/d.digits, position%d.digits
+	}
+	d.setPattern(display, position, pattern&0x7F, pattern&0x80 != 0)
+}
+
+// wipeAnimation is the Animation started by WipeTo.
+//
+// wipeAnimationは、WipeToで始めるAnimation。
+type wipeAnimation struct {
+	*transition
+	direction TransitionDirection
+}
+
+// Step reveals one more digit of the new content per delay.
+func (w *wipeAnimation) Step(d *Device, now time.Time) bool {
+	if !w.advance(now) {
+		return false
+	}
+	width := len(w.to)
+	w.draw(d, func(pos int) byte {
+		revealed := pos < w.frame
+		if w.direction == TransitionRightToLeft {
+			revealed = pos >= width-w.frame
+		}
+		if revealed {
+			return w.to[pos]
+		}
+		return w.from[pos]
+	})
+	return true
+}
+
+// WipeTo changes a display to s with a wipe: the new content replaces the
+// old one digit by digit in the given direction, one digit per stepDelay.
+// s is placed by the Alignment, as by WriteString. It is non-blocking: call
+// Update from the main loop. Starting another transition on the display
+// replaces the running one.
+//
+// WipeToは、ワイプでディスプレイをsに変える。新しい内容が指定した方向へ
+// stepDelayごとに1桁ずつ古い内容を置き換える。sはWriteStringと同様に
+// Alignmentに従って置く。ノンブロッキングなので、メインループからUpdateを
+// 呼び出す。ディスプレイで別のトランジションを始めると、動いているものを
+// 置き換える。
+func (d *Device) WipeTo(display int, s string, direction TransitionDirection, stepDelay time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	t, err := d.newTransition(display, s, d.digits, stepDelay)
+	if err != nil {
+		return err
+	}
+	d.startTransition(t, &wipeAnimation{transition: t, direction: direction})
+	return nil
+}
+
+// slideAnimation is the Animation started by SlideTo and SlideTo16.
+//
+// slideAnimationは、SlideToとSlideTo16で始めるAnimation。
+type slideAnimation struct {
+	*transition
+	direction TransitionDirection
+}
+
+// Step shifts both contents by one digit per delay.
+func (s *slideAnimation) Step(d *Device, now time.Time) bool {
+	if !s.advance(now) {
+		return false
+	}
+	width := len(s.to)
+	s.draw(d, func(pos int) byte {
+		if s.direction == TransitionRightToLeft {
+			// The old content leaves on the left, the new one enters on the right.
+			if pos < width-s.frame {
+				return s.from[pos+s.frame]
+			}
+			return s.to[pos-(width-s.frame)]
+		}
+		if pos < s.frame {
+			return s.to[width-s.frame+pos]
+		}
+		return s.from[pos-s.frame]
+	})
+	return true
+}
+
+// SlideTo changes a display to s with a slide: the old content shifts off
+// the display in the given direction while the new content shifts in behind
+// it, one digit per stepDelay. s is placed by the Alignment, as by
+// WriteString. It is non-blocking: call Update from the main loop.
+//
+// SlideToは、スライドでディスプレイをsに変える。古い内容が指定した方向へ
+// ディスプレイから出て行き、その後ろから新しい内容がstepDelayごとに1桁ずつ
+// 入ってくる。sはWriteStringと同様にAlignmentに従って置く。ノンブロッキング
+// なので、メインループからUpdateを呼び出す。
+func (d *Device) SlideTo(display int, s string, direction TransitionDirection, stepDelay time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	return d.slideTo(display, s, direction, stepDelay)
+}
+
+// SlideTo16 is like SlideTo, but slides across both displays as one
+// 16-digit strip, as WriteString16 writes it.
+//
+// SlideTo16はSlideToと同様だが、WriteString16と同じように両方の
+// ディスプレイを1本の16桁の帯としてスライドする。
+func (d *Device) SlideTo16(s string, direction TransitionDirection, stepDelay time.Duration) error {
+	return d.slideTo(combinedDisplay, s, direction, stepDelay)
+}
+
+// slideTo is the common part of SlideTo and SlideTo16.
+//
+// slideToは、SlideToとSlideTo16の共通部分。
+func (d *Device) slideTo(display int, s string, direction TransitionDirection, stepDelay time.Duration) error {
+	t, err := d.newTransition(display, s, d.width(display), stepDelay)
+	if err != nil {
+		return err
+	}
+	d.startTransition(t, &slideAnimation{transition: t, direction: direction})
+	return nil
+}
+
+// dissolveAnimation is the Animation started by DissolveTo.
+//
+// dissolveAnimationは、DissolveToで始めるAnimation。
+type dissolveAnimation struct {
+	*transition
+	duration time.Duration
+	// order has the segments that differ, as position*8+bit, in the order
+	// in which they switch.
+	// orderは、異なるセグメントを位置*8+ビットとして、切り替わる順に持つ。
+	order   []int
+	current []byte
+}
+
+// Step switches as many segments as the elapsed part of the duration.
+func (s *dissolveAnimation) Step(d *Device, now time.Time) bool {
+	if s.done {
+		return false
+	}
+	switched := len(s.order)
+	if elapsed := tickDuration(tickOf(now) - s.last); elapsed < s.duration {
+		switched = int(int64(len(s.order)) * int64(elapsed) / int64(s.duration))
+	}
+	if switched == s.frame && switched < len(s.order) {
+		return false
+	}
+	for _, segment := range s.order[s.frame:switched] {
+		s.current[segment/8] ^= 1 << (segment % 8)
+	}
+	s.frame = switched
+	s.done = switched >= len(s.order)
+	s.draw(d, func(pos int) byte {
+		return s.current[pos]
+	})
+	return true
+}
+
+// DissolveTo changes a display to s with a dissolve: the segments that
+// differ switch from the old content to the new one one by one in random
+// order, spread over duration. s is placed by the Alignment, as by
+// WriteString. It is non-blocking: call Update from the main loop.
+//
+// DissolveToは、ディゾルブでディスプレイをsに変える。異なるセグメントが
+// ランダムな順に1つずつ古い内容から新しい内容へ切り替わり、duration全体に
+// 広がる。sはWriteStringと同様にAlignmentに従って置く。ノンブロッキング
+// なので、メインループからUpdateを呼び出す。
+func (d *Device) DissolveTo(display int, s string, duration time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	t, err := d.newTransition(display, s, 0, 0)
+	if err != nil {
+		return err
+	}
+	dissolve := &dissolveAnimation{transition: t, duration: duration}
+	dissolve.current = append([]byte(nil), t.from...)
+	for pos := range t.from {
+		for bit := 0; bit < 8; bit++ {
+			if (t.from[pos]^t.to[pos])&(1<<bit) != 0 {
+				dissolve.order = append(dissolve.order, pos*8+bit)
+			}
+		}
+	}
+	rand.Shuffle(len(dissolve.order), func(i, j int) {
+		dissolve.order[i], dissolve.order[j] = dissolve.order[j], dissolve.order[i]
+	})
+	t.frames = len(dissolve.order)
+	d.startTransition(t, dissolve)
+	return nil
+}
+
+// blindsAnimation is the Animation started by BlindsTo.
+//
+// blindsAnimationは、BlindsToで始めるAnimation。
+type blindsAnimation struct {
+	*transition
+}
+
+// Step switches the digits of one more pass per delay.
+func (b *blindsAnimation) Step(d *Device, now time.Time) bool {
+	if !b.advance(now) {
+		return false
+	}
+	b.draw(d, func(pos int) byte {
+		if pos%b.frames < b.frame {
+			return b.to[pos]
+		}
+		return b.from[pos]
+	})
+	return true
+}
+
+// BlindsTo changes a display to s like opening blinds: the digits switch to
+// the new content in passes, one pass per stepDelay, and each pass switches
+// every passes-th digit. passes less than 2 is taken as 2, which switches
+// the even digits and then the odd ones. s is placed by the Alignment, as
+// by WriteString. It is non-blocking: call Update from the main loop.
+//
+// BlindsToは、ブラインドを開くようにディスプレイをsに変える。桁は
+// stepDelayごとに1回ずつ、何回かに分けて新しい内容に切り替わり、各回は
+// passes桁おきの桁を切り替える。passesが2未満なら2とし、偶数の桁、次に
+// 奇数の桁を切り替える。sはWriteStringと同様にAlignmentに従って置く。
+// ノンブロッキングなので、メインループからUpdateを呼び出す。
+func (d *Device) BlindsTo(display int, s string, passes int, stepDelay time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if passes < 2 {
+		passes = 2
+	}
+	t, err := d.newTransition(display, s, passes, stepDelay)
+	if err != nil {
+		return err
+	}
+	d.startTransition(t, &blindsAnimation{transition: t})
+	return nil
+}
+
+// crossfadeAnimation is the Animation started by CrossfadeTo.
+//
+// crossfadeAnimationは、CrossfadeToで始めるAnimation。
+type crossfadeAnimation struct {
+	*transition
+	duration time.Duration
+	// duty accumulates the share of the new content, and showingNew is the
+	// content in the buffer.
+	// dutyは新しい内容の割合を積算し、showingNewはバッファにある内容。
+	duty       int64
+	showingNew bool
+}
+
+// Step shows the old or the new content so that, over many steps, the share
+// of the new one follows the elapsed part of the duration.
+func (c *crossfadeAnimation) Step(d *Device, now time.Time) bool {
+	if c.done {
+		return false
+	}
+	elapsed := tickDuration(tickOf(now) - c.last)
+	if elapsed >= c.duration {
+		c.done = true
+		c.draw(d, nil)
+		return true
+	}
+	// Error diffusion: show the new content whenever the accumulated share
+	// reaches a whole step.
+	c.duty += int64(elapsed)
+	showNew := c.duty >= int64(c.duration)
+	if showNew {
+		c.duty -= int64(c.duration)
+	}
+	if showNew == c.showingNew {
+		return false
+	}
+	c.showingNew = showNew
+	c.draw(d, func(pos int) byte {
+		if showNew {
+			return c.to[pos]
+		}
+		return c.from[pos]
+	})
+	return true
+}
+
+// CrossfadeTo changes a display to s with a crossfade. The brightness is
+// shared by all digits, so the old and new contents are shown in turn, and
+// the new one gets a growing share of the time until it is shown alone
+// after duration. Update has to be called at a high rate, every millisecond
+// or so, for the alternation to look like a blend rather than a flicker.
+// s is placed by the Alignment, as by WriteString.
+//
+// CrossfadeToは、クロスフェードでディスプレイをsに変える。明るさはすべての
+// 桁で共通なので、古い内容と新しい内容を交互に表示し、新しい内容の時間の
+// 割合を増やしていき、duration後には新しい内容だけを表示する。交互の表示が
+// ちらつきではなく混ざって見えるように、Updateは1ミリ秒程度ごとの高い頻度で
+// 呼び出す必要がある。sはWriteStringと同様にAlignmentに従って置く。
+func (d *Device) CrossfadeTo(display int, s string, duration time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	t, err := d.newTransition(display, s, 0, 0)
+	if err != nil {
+		return err
+	}
+	d.startTransition(t, &crossfadeAnimation{transition: t, duration: duration})
+	return nil
+}
+
+// rollAnimation is the Animation started by RollTo.
+//
+// rollAnimationは、RollToで始めるAnimation。
+type rollAnimation struct {
+	*transition
+	glyphs [10]byte
+	// digits has the starting digit of each rolling position and distance
+	// how many steps it rolls; distance is 0 for the other positions.
+	// digitsは回転する各位置の最初の数字、distanceは回転する歩数を持つ。
+	// その他の位置のdistanceは0。
+	digits   []int
+	distance []int
+}
+
+// Step rolls every rolling digit on by one per delay.
+func (r *rollAnimation) Step(d *Device, now time.Time) bool {
+	if !r.advance(now) {
+		return false
+	}
+	r.draw(d, func(pos int) byte {
+		if r.distance[pos] == 0 {
+			return r.to[pos]
+		}
+		steps := min(r.frame, r.distance[pos])
+		return r.glyphs[(r.digits[pos]+steps)%10] | r.to[pos]&0x80
+	})
+	return true
+}
+
+// RollTo changes a display to what write puts on it like an odometer: each
+// digit 0-9 that changes to another digit rolls up through the digits in
+// between (3, 4, 5...), one per stepDelay, wrapping from 9 to 0. Other
+// changes are shown at the first step. write is called at once with its
+// output kept off the display, so any writer works, for example
+//
+//	d.RollTo(0, func() error { return d.WriteInt(0, count) }, 50*time.Millisecond)
+//
+// If write fails, its error is returned and the display is unchanged. It is
+// non-blocking: call Update from the main loop.
+//
+// RollToは、writeがディスプレイに書く内容へ、オドメーターのようにディスプ
+// レイを変える。別の数字に変わる0-9の各数字は、間の数字(3、4、5…)を
+// stepDelayごとに1つずつ上へ回り、9の次は0になる。その他の変化は最初の
+// 歩で表示する。writeはその出力をディスプレイに出さずにすぐ呼び出すので、
+// 上の例のようにどの書き込み関数でも使える。writeが失敗した場合はその
+// エラーを返し、ディスプレイは変更しない。ノンブロッキングなので、
+// メインループからUpdateを呼び出す。
+func (d *Device) RollTo(display int, write func() error, stepDelay time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	t, err := d.captureTransition(display, 1, stepDelay, write)
+	if err != nil {
+		return err
+	}
+	roll := &rollAnimation{
+		transition: t,
+		digits:     make([]int, len(t.to)),
+		distance:   make([]int, len(t.to)),
+	}
+	for i := range roll.glyphs {
+		roll.glyphs[i], _ = d.font.Glyph(rune('0' + i))
+	}
+	for pos := range t.to {
+		from, to := roll.digit(t.from[pos]), roll.digit(t.to[pos])
+		if from < 0 || to < 0 || from == to {
+			continue
+		}
+		roll.digits[pos] = from
+		roll.distance[pos] = (to - from + 10) % 10
+		t.frames = max(t.frames, roll.distance[pos])
+	}
+	d.startTransition(t, roll)
+	return nil
+}
+
+// digit returns the digit 0-9 a pattern shows, ignoring its decimal point,
+// or -1 if it is not a digit.
+//
+// digitは、パターンが表す0-9の数字を小数点を無視して返す。数字でなければ
+// -1を返す。
+func (r *rollAnimation) digit(pattern byte) int {
+	for i, glyph := range r.glyphs {
+		if pattern&0x7F == glyph {
+			return i
+		}
+	}
+	return -1
+}
+
+// spinAnimation is the Animation started by SpinTo.
+//
+// spinAnimationは、SpinToで始めるAnimation。
+type spinAnimation struct {
+	*transition
+	glyphs     [10]byte
+	spinFrames int
+}
+
+// Step shows random digits on the digits that have not settled yet.
+func (s *spinAnimation) Step(d *Device, now time.Time) bool {
+	if !s.advance(now) {
+		return false
+	}
+	s.draw(d, func(pos int) byte {
+		if s.frame >= s.spinFrames*(pos+1) {
+			return s.to[pos]
+		}
+		return s.glyphs[rand.Intn(len(s.glyphs))]
+	})
+	return true
+}
+
+// SpinTo changes a display to s like a slot machine: all digits spin
+// through random digits, one per frameDelay, and settle on s one by one from
+// the left, each spinFrames frames after the previous one. spinFrames less
+// than 1 is taken as 1. s is placed by the Alignment, as by WriteString. It
+// is non-blocking: call Update from the main loop.
+//
+// SpinToは、スロットマシンのようにディスプレイをsに変える。すべての桁が
+// frameDelayごとにランダムな数字で回り、左から1桁ずつ、前の桁から
+// spinFramesフレーム後にsに止まる。spinFramesが1未満なら1とする。sは
+// WriteStringと同様にAlignmentに従って置く。ノンブロッキングなので、
+// メインループからUpdateを呼び出す。
+func (d *Device) SpinTo(display int, s string, spinFrames int, frameDelay time.Duration) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if spinFrames < 1 {
+		spinFrames = 1
+	}
+	t, err := d.newTransition(display, s, spinFrames*d.digits, frameDelay)
+	if err != nil {
+		return err
+	}
+	spin := &spinAnimation{transition: t, spinFrames: spinFrames}
+	for i := range spin.glyphs {
+		spin.glyphs[i], _ = d.font.Glyph(rune('0' + i))
+	}
+	d.startTransition(t, spin)
+	return nil
+}
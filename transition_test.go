@@ -0,0 +1,237 @@
+package ht16k33
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWipeTo verifies that a wipe reveals the new content one digit per step in both directions.
+func TestWipeTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "88888888")
+
+	if err := device.WipeTo(0, "12345678", TransitionLeftToRight, 0); err != nil {
+		t.Fatalf("WipeTo() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "88888888")
+	device.Update()
+	assertDisplay(t, device, 0, "18888888")
+	device.Update()
+	assertDisplay(t, device, 0, "12888888")
+	for device.Update() {
+	}
+	assertDisplay(t, device, 0, "12345678")
+
+	device.WipeTo(0, "", TransitionRightToLeft, 0)
+	device.Update()
+	device.Update()
+	assertDisplay(t, device, 0, "123456  ")
+	for device.Update() {
+	}
+	assertDisplay(t, device, 0, "        ")
+}
+
+// TestWipeToReplaces verifies that a new transition replaces the running one on the same display only.
+func TestWipeToReplaces(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	device.WipeTo(0, "11111111", TransitionLeftToRight, 0)
+	device.WipeTo(1, "22222222", TransitionLeftToRight, 0)
+	device.Update()
+	device.WipeTo(0, "33333333", TransitionLeftToRight, 0)
+	device.Update()
+	assertDisplay(t, device, 0, "3")
+	for device.Update() {
+	}
+	assertDisplay(t, device, 0, "33333333")
+	assertDisplay(t, device, 1, "22222222")
+
+	if err := device.WipeTo(2, "1", TransitionLeftToRight, 0); err != ErrInvalidDisplay {
+		t.Errorf("FAIL: WipeTo() with an invalid display should return ErrInvalidDisplay, got %v", err)
+	}
+}
+
+// TestSlideTo verifies that the old content shifts out while the new content shifts in.
+func TestSlideTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "12345678")
+
+	device.SlideTo(0, "AbCdEF", TransitionRightToLeft, 0)
+	device.Update()
+	assertDisplay(t, device, 0, "2345678A")
+	device.Update()
+	assertDisplay(t, device, 0, "345678Ab")
+	for device.Update() {
+	}
+	assertDisplay(t, device, 0, "AbCdEF  ")
+
+	device.SlideTo(0, "12", TransitionLeftToRight, 0)
+	device.Update()
+	assertDisplay(t, device, 0, " AbCdEF ")
+	device.Update()
+	assertDisplay(t, device, 0, "  AbCdEF")
+	device.Update()
+	assertDisplay(t, device, 0, "   AbCdE")
+	for device.Update() {
+	}
+	assertDisplay(t, device, 0, "12      ")
+}
+
+// TestSlideTo16 verifies that a slide flows from one display into the other.
+func TestSlideTo16(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(1, "       1")
+
+	device.SlideTo16("2", TransitionRightToLeft, 0)
+	device.Update()
+	assertDisplay(t, device, 0, "")
+	assertDisplay(t, device, 1, "      12")
+	for i := 0; i < 8; i++ {
+		device.Update()
+	}
+	assertDisplay(t, device, 0, "      12")
+	assertDisplay(t, device, 1, "")
+	for device.Update() {
+	}
+	assertDisplay(t, device, 0, "2")
+	assertDisplay(t, device, 1, "")
+}
+
+// TestDissolveTo verifies that a dissolve switches the differing segments over its duration.
+func TestDissolveTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "8")
+
+	device.DissolveTo(0, "1", time.Hour)
+	dissolve := device.transitions[0]
+	if len(dissolve.from) != 8 || dissolve.frames != 5 {
+		t.Fatalf("FAIL: '8' to '1' should switch 5 segments, got %d", dissolve.frames)
+	}
+
+	// Halfway through, 2 of the 5 segments have switched off.
+	device.animator.Step(device, device.animationTime().Add(30*time.Minute))
+	if got := device.digitPattern(0, 0); bits(got) != 5 || got&(segB|segC) != segB|segC {
+		t.Errorf("FAIL: Halfway frame is wrong, got %x", got)
+	}
+
+	device.animator.Step(device, device.animationTime().Add(time.Hour))
+	assertDisplay(t, device, 0, "1")
+	if device.IsAnimating() {
+		t.Errorf("FAIL: Dissolve should finish after its duration")
+	}
+}
+
+// bits returns the number of set bits of b.
+func bits(b byte) int {
+	n := 0
+	for ; b != 0; b &= b - 1 {
+		n++
+	}
+	return n
+}
+
+// TestBlindsTo verifies that the digits switch in alternating passes.
+func TestBlindsTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "88888888")
+
+	device.BlindsTo(0, "12345678", 0, 0)
+	device.Update()
+	assertDisplay(t, device, 0, "18385878")
+	if device.Update() {
+		t.Errorf("FAIL: Update() should return false after the last pass")
+	}
+	assertDisplay(t, device, 0, "12345678")
+
+	device.BlindsTo(0, "", 3, 0)
+	device.Update()
+	assertDisplay(t, device, 0, " 23 56 8")
+	device.Update()
+	assertDisplay(t, device, 0, "  3  6  ")
+	device.Update()
+	assertDisplay(t, device, 0, "")
+}
+
+// TestCrossfadeTo verifies that the new content gets a growing share of the steps.
+func TestCrossfadeTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteString(0, "1")
+
+	device.CrossfadeTo(0, "2", time.Second)
+	crossfade := device.transitions[0]
+	start := device.animationTime()
+
+	// Count how often the new content is shown in each quarter of the duration.
+	var shares [4]int
+	for ms := 0; ms < 1000; ms++ {
+		device.animator.Step(device, start.Add(time.Duration(ms)*time.Millisecond))
+		if device.digitPattern(0, 0) == crossfade.to[0] {
+			shares[ms/250]++
+		}
+	}
+	for i := 1; i < len(shares); i++ {
+		if shares[i] <= shares[i-1] {
+			t.Errorf("FAIL: Share of the new content should grow, got %v", shares)
+		}
+	}
+
+	device.animator.Step(device, start.Add(time.Second))
+	assertDisplay(t, device, 0, "2")
+	if device.IsAnimating() {
+		t.Errorf("FAIL: Crossfade should finish after its duration")
+	}
+}
+
+// TestRollTo verifies that changed digits roll through the digits in between.
+func TestRollTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteInt(0, 38)
+
+	err := device.RollTo(0, func() error { return device.WriteInt(0, 41) }, 0)
+	if err != nil {
+		t.Fatalf("RollTo() returned an unexpected error: %v", err)
+	}
+	assertDisplay(t, device, 0, "      38")
+	expected := []string{"      49", "      40", "      41"}
+	for i, want := range expected {
+		device.Update()
+		assertDisplay(t, device, 0, want)
+		if i < len(expected)-1 && !device.IsAnimating() {
+			t.Fatalf("FAIL: Roll finished early at step %d", i)
+		}
+	}
+	if device.IsAnimating() {
+		t.Errorf("FAIL: Roll should finish after the longest roll")
+	}
+
+	// A digit appearing in a blank position is shown at the first step.
+	device.RollTo(0, func() error { return device.WriteInt(0, 100) }, 0)
+	device.Update()
+	assertDisplay(t, device, 0, "     152")
+
+	if err := device.RollTo(0, func() error { return ErrOverflow }, 0); err != ErrOverflow {
+		t.Errorf("FAIL: RollTo() should return the error of write, got %v", err)
+	}
+}
+
+// TestSpinTo verifies that the digits settle on the target one by one from the left.
+func TestSpinTo(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	device.SpinTo(0, "12345678", 2, 0)
+	steps := 0
+	for device.Update() {
+		steps++
+		settled := steps / 2
+		for pos := 0; pos < settled; pos++ {
+			want, _ := device.font.Glyph(rune('1' + pos))
+			if got := device.digitPattern(0, pos); got != want {
+				t.Fatalf("FAIL: Digit %d should have settled after %d steps!\nExpected: %x\nGot:      %x", pos, steps, want, got)
+			}
+		}
+	}
+	if steps != 15 {
+		t.Errorf("FAIL: Spin should take 16 steps, got %d", steps+1)
+	}
+	assertDisplay(t, device, 0, "12345678")
+}
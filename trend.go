@@ -0,0 +1,115 @@
+package ht16k33
+
+import "math"
+
+// TrendDirection is the direction a value has been moving in.
+//
+// TrendDirectionは、値が動いている方向。
+type TrendDirection uint8
+
+const (
+	// TrendSteady means the value stayed within the threshold.
+	TrendSteady TrendDirection = iota
+	// TrendUp means the value went up by more than the threshold.
+	TrendUp
+	// TrendDown means the value went down by more than the threshold.
+	TrendDown
+)
+
+// trendGlyphs are the arrow-ish patterns of each TrendDirection: '-' for
+// steady, a raised '^' for up and a lowered 'u' for down.
+//
+// trendGlyphsは、各TrendDirectionの矢印風のパターン。横ばいは'-'、上昇は
+// 上寄りの'^'、下降は下寄りの'u'。
+var trendGlyphs = [...]cell{
+	TrendSteady: {pattern: segG},
+	TrendUp:     {pattern: segF | segA | segB},
+	TrendDown:   {pattern: segE | segD | segC},
+}
+
+// maxTrendHistory is the most values a Trend remembers.
+const maxTrendHistory = 16
+
+// Trend keeps a short history of values to tell which way they are moving.
+// It has a fixed size, so it needs no allocation after NewTrend.
+//
+// Trendは、値がどちらに動いているかを判断するため、短い履歴を保持する。
+// 大きさは固定なので、NewTrendの後はメモリを割り当てない。
+type Trend struct {
+	history   [maxTrendHistory]float64
+	size      int
+	count     int
+	next      int
+	threshold float64
+}
+
+// NewTrend creates a Trend that compares the newest of the last size values
+// (2-16) with the oldest, and reports a change only if it is larger than
+// threshold, so that noise does not flip the arrow.
+//
+// NewTrendは、直近size個(2-16)の値のうち最新のものと最古のものを比べる
+// Trendを作る。ノイズで矢印が変わらないよう、差がthresholdより大きい場合
+// だけ変化とみなす。
+func NewTrend(size int, threshold float64) *Trend {
+	if size < 2 {
+		size = 2
+	}
+	if size > maxTrendHistory {
+		size = maxTrendHistory
+	}
+	return &Trend{size: size, threshold: math.Abs(threshold)}
+}
+
+// Add records a new value and returns the resulting direction.
+//
+// Addは、新しい値を記録し、その結果の方向を返す。
+func (t *Trend) Add(v float64) TrendDirection {
+	t.history[t.next] = v
+	t.next = (t.next + 1) % t.size
+	if t.count < t.size {
+		t.count++
+	}
+	return t.Direction()
+}
+
+// Direction returns the direction of the recorded values.
+//
+// Directionは、記録した値の方向を返す。
+func (t *Trend) Direction() TrendDirection {
+	if t.count < 2 {
+		return TrendSteady
+	}
+	newest := t.history[(t.next+t.size-1)%t.size]
+	oldest := t.history[(t.next+t.size-t.count)%t.size]
+	switch diff := newest - oldest; {
+	case diff > t.threshold:
+		return TrendUp
+	case diff < -t.threshold:
+		return TrendDown
+	}
+	return TrendSteady
+}
+
+// WriteTrend adds v to trend and displays it right-aligned with prec digits
+// after the decimal point, followed by an arrow-ish glyph for the direction,
+// such as "23.4^". The precision is reduced if needed, like WriteFloat. If
+// v does not fit, the display is left unchanged and ErrOverflow is returned.
+//
+// WriteTrendは、vをtrendに追加し、小数点以下prec桁で右詰めに表示して、後に
+// 方向を表す矢印風の記号を付ける("23.4^"など)。WriteFloatと同様に必要なら
+// 精度を下げる。vが収まらない場合はディスプレイを変更せずにErrOverflowを
+// 返す。
+func (d *Device) WriteTrend(display int, v float64, prec int, trend *Trend) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ErrOverflow
+	}
+	direction := trend.Add(v)
+	text, ok := d.formatFloat(v, prec, d.digits-1)
+	if !ok {
+		return ErrOverflow
+	}
+	return d.writeNumberSuffix(display, text, []cell{trendGlyphs[direction]})
+}
@@ -0,0 +1,47 @@
+package ht16k33
+
+import "testing"
+
+// TestTrend verifies the direction over the history window.
+func TestTrend(t *testing.T) {
+	trend := NewTrend(3, 0.5)
+
+	steps := []struct {
+		v        float64
+		expected TrendDirection
+	}{
+		{20, TrendSteady},
+		{20.2, TrendSteady},
+		{21, TrendUp},
+		{21.2, TrendUp},
+		{21.1, TrendSteady}, // 20.2 has left the window
+		{20, TrendDown},
+	}
+	for _, step := range steps {
+		if got := trend.Add(step.v); got != step.expected {
+			t.Errorf("FAIL: Add(%v) returned the wrong direction!\nExpected: %d\nGot:      %d", step.v, step.expected, got)
+		}
+	}
+}
+
+// TestWriteTrend verifies the value and the arrow glyph.
+func TestWriteTrend(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	trend := NewTrend(4, 0.1)
+	arrows := fallbackFont{MapFont{'^': segF | segA | segB, 'u': segE | segD | segC}}
+
+	device.WriteTrend(0, 23.1, 1, trend)
+	assertDisplay(t, device, 0, "    23.1-")
+	device.WriteTrend(0, 23.4, 1, trend)
+
+	expected := newTestDevice(t, &mockI2C{})
+	expected.SetFont(arrows)
+	expected.WriteString(0, "    23.4^")
+	if device.buffer != expected.buffer {
+		t.Errorf("FAIL: WriteTrend() is wrong!\nExpected: %x\nGot:      %x", expected.buffer[:], device.buffer[:])
+	}
+
+	if err := device.WriteTrend(0, 1e9, 1, trend); err != ErrOverflow {
+		t.Errorf("FAIL: WriteTrend(1e9) should return ErrOverflow, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package ht16k33
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidUnit is returned by WriteTemperature for a unit other than 'C'
+// and 'F'.
+var ErrInvalidUnit = errors.New("ht16k33: invalid unit")
+
+// Unit suffixes drawn with their own patterns, so that they also work with
+// fonts that lack them, such as the ht16k33_minfont build.
+//
+// 単位の接尾辞。ht16k33_minfontのビルドなど、それらを持たないフォントでも
+// 使えるように独自のパターンで描く。
+var (
+	suffixCelsius    = []cell{{pattern: segA | segB | segF | segG}, {pattern: segA | segD | segE | segF}}        // "°C"
+	suffixFahrenheit = []cell{{pattern: segA | segB | segF | segG}, {pattern: segA | segE | segF | segG}}        // "°F"
+	suffixPercent    = []cell{{pattern: segA | segB | segF | segG}, {pattern: segC | segD | segE | segG}}        // "%", as an upper and a lower ring
+	suffixPC         = []cell{{pattern: segA | segB | segE | segF | segG}, {pattern: segA | segD | segE | segF}} // "PC"
+)
+
+// PercentStyle decides how WritePercent shows the percent sign.
+//
+// PercentStyleは、WritePercentでパーセント記号をどう表示するかを決める。
+type PercentStyle uint8
+
+const (
+	// PercentGlyph approximates '%' with an upper and a lower ring on two
+	// digits (default).
+	PercentGlyph PercentStyle = iota
+	// PercentPC shows "PC", which some readers find clearer.
+	PercentPC
+)
+
+// SetPercentStyle sets how WritePercent shows the percent sign.
+//
+// SetPercentStyleは、WritePercentでパーセント記号をどう表示するかを設定する。
+func (d *Device) SetPercentStyle(style PercentStyle) {
+	d.percentStyle = style
+}
+
+// WriteTemperature displays a temperature given in degrees Celsius,
+// right-aligned and followed by "°C", or converted and followed by "°F" if
+// unit is 'F'. It is rounded to one decimal, or to whole degrees if that
+// does not fit. If it does not fit at all, the display is left unchanged
+// and ErrOverflow is returned.
+//
+// WriteTemperatureは、摂氏で与えた温度を右詰めで表示し、後に"°C"を付ける。
+// unitが'F'なら華氏に変換して"°F"を付ける。小数第1位に丸め、収まらなければ
+// 整数に丸める。まったく収まらない場合はディスプレイを変更せずにErrOverflow
+// を返す。
+func (d *Device) WriteTemperature(display int, celsius float64, unit rune) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	var suffix []cell
+	switch unit {
+	case 'C':
+		suffix = suffixCelsius
+	case 'F':
+		suffix = suffixFahrenheit
+		celsius = celsius*9/5 + 32
+	default:
+		return ErrInvalidUnit
+	}
+	if math.IsNaN(celsius) || math.IsInf(celsius, 0) {
+		return ErrOverflow
+	}
+	text, ok := d.formatFloat(celsius, 1, d.digits-len(suffix))
+	if !ok {
+		return ErrOverflow
+	}
+	return d.writeNumberSuffix(display, text, suffix)
+}
+
+// WritePercent displays a percentage, such as a battery level or humidity,
+// right-aligned and followed by a percent sign (see SetPercentStyle). pct
+// is clamped to 0-100 and shown with one decimal if it fits.
+//
+// WritePercentは、電池残量や湿度などの割合を右詰めで表示し、後にパーセント
+// 記号を付ける(SetPercentStyleを参照)。pctは0-100に制限し、収まれば小数第1
+// 位まで表示する。
+func (d *Device) WritePercent(display int, pct float64) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	if math.IsNaN(pct) {
+		return ErrOverflow
+	}
+	pct = math.Max(0, math.Min(100, pct))
+	suffix := suffixPercent
+	if d.percentStyle == PercentPC {
+		suffix = suffixPC
+	}
+	text, ok := d.formatFloat(pct, 1, d.digits-len(suffix))
+	if !ok {
+		return ErrOverflow
+	}
+	return d.writeNumberSuffix(display, text, suffix)
+}
+
+// Battery glyphs: the sides of a battery standing upright, filled from the
+// bottom with one, two or three bars.
+//
+// 電池の記号。立てた電池の側面を、下から1本、2本、3本の横棒で満たしていく。
+var batteryGlyphs = [...]byte{
+	segB | segC | segE | segF,                      // Empty
+	segB | segC | segE | segF | segD,               // Low
+	segB | segC | segE | segF | segD | segG,        // Half
+	segB | segC | segE | segF | segD | segG | segA, // Full
+}
+
+// BatteryGlyph returns the segment pattern of a one-digit battery for a
+// charge level in percent: empty below 10%, low below 40%, half below 75%
+// and full from there. It can be shown with SetSegments.
+//
+// BatteryGlyphは、充電量(パーセント)に対応する1桁の電池のセグメント
+// パターンを返す。10%未満は空、40%未満は少、75%未満は半分、それ以上は満。
+// SetSegmentsで表示できる。
+func BatteryGlyph(pct float64) byte {
+	switch {
+	case pct < 10 || math.IsNaN(pct):
+		return batteryGlyphs[0]
+	case pct < 40:
+		return batteryGlyphs[1]
+	case pct < 75:
+		return batteryGlyphs[2]
+	}
+	return batteryGlyphs[3]
+}
+
+// WriteBattery clears the display and shows a battery glyph for pct on the
+// leftmost digit. With showValue, pct is also shown as a whole percentage,
+// clamped to 0-100, right-aligned on the remaining digits.
+//
+// WriteBatteryは、ディスプレイをクリアし、左端の桁にpctに対応する電池の記号を
+// 表示する。showValueがtrueなら、0-100に制限したpctを整数のパーセントで残りの
+// 桁に右詰めで表示する。
+func (d *Device) WriteBattery(display int, pct float64, showValue bool) error {
+	if display < 0 || display >= d.displays {
+		return ErrInvalidDisplay
+	}
+	var cells []cell
+	if showValue && !math.IsNaN(pct) {
+		text := formatRounded(math.Max(0, math.Min(100, pct)), 0, d.rounding)
+		var err error
+		if cells, _, err = d.layoutString(text); err != nil {
+			return err
+		}
+	}
+	d.clearDisplay(display)
+	d.setPattern(display, 0, BatteryGlyph(pct), false)
+	if start := d.digits - len(cells); cells != nil && start > 0 {
+		d.writeCells(display, start, cells)
+	}
+	d.autoDisplay()
+	return nil
+}
@@ -0,0 +1,101 @@
+package ht16k33
+
+import "testing"
+
+// TestWriteTemperature verifies the rounding and the unit suffixes.
+func TestWriteTemperature(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+
+	tests := []struct {
+		celsius  float64
+		unit     rune
+		expected string
+	}{
+		{21.46, 'C', "   21.5°C"},
+		{-3, 'C', "   -3.0°C"},
+		{100, 'F', "  212.0°F"},
+		{123456.7, 'C', "123457°C"},
+	}
+	for _, test := range tests {
+		if err := device.WriteTemperature(0, test.celsius, test.unit); err != nil {
+			t.Fatalf("WriteTemperature(%v, %q) returned an unexpected error: %v", test.celsius, test.unit, err)
+		}
+		assertDisplayFont(t, device, 0, glyphFont, test.expected)
+	}
+
+	if err := device.WriteTemperature(0, 20, 'K'); err != ErrInvalidUnit {
+		t.Errorf("FAIL: WriteTemperature() with unit K should return ErrInvalidUnit, got %v", err)
+	}
+	if err := device.WriteTemperature(0, 1e7, 'C'); err != ErrOverflow {
+		t.Errorf("FAIL: WriteTemperature(1e7) should return ErrOverflow, got %v", err)
+	}
+}
+
+// TestWritePercent verifies the clamping and the percent styles.
+func TestWritePercent(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	tests := []struct {
+		pct      float64
+		expected string
+	}{
+		{55, "   55.0ou"},
+		{150, "  100.0ou"},
+		{-3, "    0.0ou"},
+	}
+	for _, test := range tests {
+		if err := device.WritePercent(0, test.pct); err != nil {
+			t.Fatalf("WritePercent(%v) returned an unexpected error: %v", test.pct, err)
+		}
+		assertDisplayFont(t, device, 0, glyphFont, test.expected)
+	}
+
+	device.SetPercentStyle(PercentPC)
+	device.WritePercent(0, 100)
+	assertDisplayFont(t, device, 0, glyphFont, "  100.0PC")
+}
+
+// TestBatteryGlyph verifies the mapping from charge level to glyph.
+func TestBatteryGlyph(t *testing.T) {
+	tests := []struct {
+		pct      float64
+		expected byte
+	}{
+		{0, batteryGlyphs[0]},
+		{25, batteryGlyphs[1]},
+		{50, batteryGlyphs[2]},
+		{100, batteryGlyphs[3]},
+	}
+	for _, test := range tests {
+		if got := BatteryGlyph(test.pct); got != test.expected {
+			t.Errorf("FAIL: BatteryGlyph(%v) is wrong!\nExpected: %x\nGot:      %x", test.pct, test.expected, got)
+		}
+	}
+}
+
+// TestWriteBattery verifies the glyph and the optional readout.
+func TestWriteBattery(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	device.WriteBattery(0, 87.4, true)
+
+	expected := newTestDevice(t, &mockI2C{})
+	expected.WriteString(0, "      87")
+	expected.SetSegments(0, 0, batteryGlyphs[3], false)
+	if device.buffer != expected.buffer {
+		t.Errorf("FAIL: WriteBattery() is wrong!\nExpected: %x\nGot:      %x", expected.buffer[:], device.buffer[:])
+	}
+
+	device.WriteBattery(0, 5, false)
+	expected.ClearOnDisplay(0)
+	expected.SetSegments(0, 0, batteryGlyphs[0], false)
+	if device.buffer != expected.buffer {
+		t.Errorf("FAIL: WriteBattery() without value is wrong!\nExpected: %x\nGot:      %x", expected.buffer[:], device.buffer[:])
+	}
+
+	// A readout the font cannot show leaves the display as it was.
+	device.SetFont(MapFont{})
+	device.SetUnknownRunePolicy(UnknownError)
+	before := device.buffer
+	if err := device.WriteBattery(0, 50, true); err == nil || device.buffer != before {
+		t.Errorf("FAIL: WriteBattery() should return an error and keep the buffer, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package ht16k33
+
+// ValueDisplay shows a stream of samples, such as readings from a noisy
+// sensor, with WriteFloat, but only sends the display when what is shown
+// actually changes. This avoids I2C traffic and flicker when samples come
+// in faster than the rounded value changes.
+//
+// ValueDisplayは、ノイズの多いセンサーの読み取り値などのサンプルの流れを
+// WriteFloatで表示するが、表示内容が実際に変わったときだけディスプレイに
+// 送る。丸めた値が変わるより速くサンプルが来ても、I2Cの通信とちらつきを
+// 避けられる。
+type ValueDisplay struct {
+	device  *Device
+	display int
+	prec    int
+	shown   bool
+}
+
+// NewValueDisplay creates a ValueDisplay that writes samples to a display
+// with prec digits after the decimal point. All settings of the Device,
+// such as the rounding mode and the range, apply.
+//
+// NewValueDisplayは、サンプルを小数点以下prec桁でディスプレイに書き込む
+// ValueDisplayを作る。丸め方や範囲など、Deviceの設定はすべて適用される。
+func NewValueDisplay(device *Device, display int, prec int) *ValueDisplay {
+	return &ValueDisplay{device: device, display: display, prec: prec}
+}
+
+// Update writes a sample to the buffer and sends the display if the result
+// differs from what was sent before. It returns true if it sent the
+// display. The auto flush of the Device is not used.
+//
+// Updateは、サンプルをバッファに書き込み、以前に送った内容と異なる場合だけ
+// ディスプレイに送る。送った場合はtrueを返す。Deviceの自動転送は使わない。
+func (v *ValueDisplay) Update(sample float64) (bool, error) {
+	d := v.device
+	before := d.buffer
+	autoFlush := d.autoFlush
+	d.autoFlush = false
+	err := d.WriteFloat(v.display, sample, v.prec)
+	d.autoFlush = autoFlush
+	if err != nil {
+		return false, err
+	}
+	if v.shown && d.buffer == before {
+		return false, nil
+	}
+	v.shown = true
+	d.Display()
+	return true, nil
+}
+
+// Invalidate makes the next Update send the display even if the value is
+// unchanged, for example after other code has written to the display.
+//
+// Invalidateは、値が変わらなくても次のUpdateでディスプレイに送るようにする。
+// 他のコードがディスプレイに書き込んだ後などに使う。
+func (v *ValueDisplay) Invalidate() {
+	v.shown = false
+}
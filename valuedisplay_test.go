@@ -0,0 +1,38 @@
+package ht16k33
+
+import "testing"
+
+// TestValueDisplay verifies that the display is only sent when the shown value changes.
+func TestValueDisplay(t *testing.T) {
+	device := newTestDevice(t, &mockI2C{})
+	value := NewValueDisplay(device, 0, 1)
+
+	samples := []struct {
+		v        float64
+		expected bool
+	}{
+		{21.04, true}, // First sample
+		{20.96, false},
+		{21.01, false},
+		{21.06, true},
+		{21.11, false},
+	}
+	for _, sample := range samples {
+		sent, err := value.Update(sample.v)
+		if err != nil {
+			t.Fatalf("Update(%v) returned an unexpected error: %v", sample.v, err)
+		}
+		if sent != sample.expected {
+			t.Errorf("FAIL: Update(%v) sent the display: %v, expected %v", sample.v, sent, sample.expected)
+		}
+	}
+	assertDisplay(t, device, 0, "     21.1")
+	if got := device.Stats().Transactions; got != 2 {
+		t.Errorf("FAIL: Wrong number of I2C transactions!\nExpected: %d\nGot:      %d", 2, got)
+	}
+
+	value.Invalidate()
+	if sent, _ := value.Update(21.1); !sent {
+		t.Errorf("FAIL: Update() after Invalidate should send the display")
+	}
+}